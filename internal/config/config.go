@@ -1,14 +1,319 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/goccy/go-yaml"
 	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
 )
 
-func LoadConfig() {
-	err := godotenv.Load()
+type Config struct {
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	Database  DatabaseConfig  `yaml:"database" toml:"database"`
+	JWT       JWTConfig       `yaml:"jwt" toml:"jwt"`
+	LLM       LLMConfig       `yaml:"llm" toml:"llm"`
+	YouTube   YouTubeConfig   `yaml:"youtube" toml:"youtube"`
+	Ingestion IngestionConfig `yaml:"ingestion" toml:"ingestion"`
+	Streak    StreakConfig    `yaml:"streak" toml:"streak"`
+}
+
+type ServerConfig struct {
+	Port    string `yaml:"port" toml:"port"`
+	GinMode string `yaml:"gin_mode" toml:"gin_mode"`
+}
+
+type DatabaseConfig struct {
+	URL      string `yaml:"url" toml:"url"`
+	Host     string `yaml:"host" toml:"host"`
+	Port     string `yaml:"port" toml:"port"`
+	User     string `yaml:"user" toml:"user"`
+	Password string `yaml:"password" toml:"password"`
+	Name     string `yaml:"name" toml:"name"`
+	SSLMode  string `yaml:"sslmode" toml:"sslmode"`
+	TimeZone string `yaml:"timezone" toml:"timezone"`
+}
+
+type JWTConfig struct {
+	Secret     string   `yaml:"secret" toml:"secret"`
+	TTL        Duration `yaml:"ttl" toml:"ttl"`
+	RefreshTTL Duration `yaml:"refresh_ttl" toml:"refresh_ttl"`
+}
+
+// Duration membungkus time.Duration supaya bisa ditulis sebagai "15m" atau
+// "24h" di file YAML maupun TOML.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+type LLMConfig struct {
+	Provider string `yaml:"provider" toml:"provider"`
+	APIKey   string `yaml:"api_key" toml:"api_key"`
+	Model    string `yaml:"model" toml:"model"`
+	BaseURL  string `yaml:"base_url" toml:"base_url"`
+
+	EmbeddingModel string `yaml:"embedding_model" toml:"embedding_model"`
+}
+
+type YouTubeConfig struct {
+	APIKey string `yaml:"api_key" toml:"api_key"`
+}
+
+type IngestionConfig struct {
+	Workers int `yaml:"workers" toml:"workers"`
+}
+
+type StreakConfig struct {
+	CheckInterval   Duration `yaml:"check_interval" toml:"check_interval"`
+	BatchSize       int      `yaml:"batch_size" toml:"batch_size"`
+	FreezeEveryDays int      `yaml:"freeze_every_days" toml:"freeze_every_days"`
+	MaxFreezes      int      `yaml:"max_freezes" toml:"max_freezes"`
+}
+
+// LoadConfig membaca konfigurasi dari file opsional (CONFIG_FILE, .yaml/.yml
+// atau .toml), lalu menimpanya dengan environment variable. File .env dimuat
+// lebih dulu jika ada, kecuali saat berjalan di Railway.
+func LoadConfig() (*Config, error) {
+	if _, isRunningOnRailway := os.LookupEnv("RAILWAY_ENVIRONMENT"); !isRunningOnRailway {
+		if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("gagal memuat file .env: %w", err)
+		}
+	}
+
+	cfg := defaultConfig()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+		log.Printf("Konfigurasi dimuat dari %s", path)
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	var problems []string
+
+	if c.Server.Port == "" {
+		problems = append(problems, "PORT wajib diisi")
+	}
+	switch c.Server.GinMode {
+	case "debug", "release", "test":
+	default:
+		problems = append(problems, fmt.Sprintf("GIN_MODE %q tidak valid (debug, release, atau test)", c.Server.GinMode))
+	}
+
+	if c.Database.URL == "" {
+		if c.Database.Host == "" {
+			problems = append(problems, "DATABASE_URL atau DB_HOST wajib diisi")
+		}
+		if c.Database.User == "" {
+			problems = append(problems, "DATABASE_URL atau DB_USER wajib diisi")
+		}
+		if c.Database.Name == "" {
+			problems = append(problems, "DATABASE_URL atau DB_NAME wajib diisi")
+		}
+	}
+
+	if c.JWT.Secret == "" {
+		problems = append(problems, "JWT_SECRET wajib diisi")
+	} else if len(c.JWT.Secret) < 32 {
+		problems = append(problems, "JWT_SECRET minimal 32 karakter")
+	}
+	if c.JWT.TTL <= 0 {
+		problems = append(problems, "JWT_TTL harus lebih dari 0")
+	}
+	if c.JWT.RefreshTTL <= c.JWT.TTL {
+		problems = append(problems, "JWT_REFRESH_TTL harus lebih lama dari JWT_TTL")
+	}
+
+	switch c.LLM.Provider {
+	case "gemini", "openai":
+		if c.LLM.APIKey == "" && c.LLM.Provider == "gemini" {
+			problems = append(problems, "GEMINI_API_KEY atau LLM_API_KEY wajib diisi untuk provider gemini")
+		}
+		if c.LLM.Model == "" && c.LLM.Provider == "openai" {
+			problems = append(problems, "LLM_MODEL wajib diisi untuk provider openai")
+		}
+	case "fake":
+	default:
+		problems = append(problems, fmt.Sprintf("LLM_PROVIDER %q tidak dikenal (gemini, openai, atau fake)", c.LLM.Provider))
+	}
+
+	if c.Ingestion.Workers < 1 {
+		problems = append(problems, "INGESTION_WORKERS minimal 1")
+	}
+
+	if c.Streak.CheckInterval < Duration(time.Minute) {
+		problems = append(problems, "STREAK_CHECK_INTERVAL minimal 1m")
+	}
+	if c.Streak.BatchSize < 1 {
+		problems = append(problems, "STREAK_BATCH_SIZE minimal 1")
+	}
+	if c.Streak.FreezeEveryDays < 1 {
+		problems = append(problems, "STREAK_FREEZE_EVERY_DAYS minimal 1")
+	}
+	if c.Streak.MaxFreezes < 0 {
+		problems = append(problems, "STREAK_MAX_FREEZES tidak boleh negatif")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("konfigurasi tidak valid: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:    "8080",
+			GinMode: "debug",
+		},
+		Database: DatabaseConfig{
+			Port:     "5432",
+			SSLMode:  "disable",
+			TimeZone: "UTC",
+		},
+		JWT: JWTConfig{
+			TTL:        Duration(15 * time.Minute),
+			RefreshTTL: Duration(30 * 24 * time.Hour),
+		},
+		LLM: LLMConfig{
+			Provider: "gemini",
+		},
+		Ingestion: IngestionConfig{
+			Workers: 2,
+		},
+		Streak: StreakConfig{
+			CheckInterval:   Duration(15 * time.Minute),
+			BatchSize:       200,
+			FreezeEveryDays: 7,
+			MaxFreezes:      2,
+		},
+	}
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("gagal membaca file konfigurasi %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("format file konfigurasi %s tidak didukung (gunakan .yaml, .yml, atau .toml)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("gagal parsing file konfigurasi %s: %w", path, err)
+	}
+	return nil
+}
+
+func applyEnv(cfg *Config) error {
+	setString(&cfg.Server.Port, "PORT")
+	setString(&cfg.Server.GinMode, "GIN_MODE")
+
+	setString(&cfg.Database.URL, "DATABASE_URL")
+	setString(&cfg.Database.Host, "DB_HOST")
+	setString(&cfg.Database.Port, "DB_PORT")
+	setString(&cfg.Database.User, "DB_USER")
+	setString(&cfg.Database.Password, "DB_PASSWORD")
+	setString(&cfg.Database.Name, "DB_NAME")
+	setString(&cfg.Database.SSLMode, "DB_SSLMODE")
+	setString(&cfg.Database.TimeZone, "DB_TIMEZONE")
+
+	setString(&cfg.JWT.Secret, "JWT_SECRET")
+	if err := setDuration(&cfg.JWT.TTL, "JWT_TTL"); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.JWT.RefreshTTL, "JWT_REFRESH_TTL"); err != nil {
+		return err
+	}
+
+	setString(&cfg.LLM.Provider, "LLM_PROVIDER")
+	setString(&cfg.LLM.APIKey, "GEMINI_API_KEY")
+	setString(&cfg.LLM.APIKey, "LLM_API_KEY")
+	setString(&cfg.LLM.Model, "LLM_MODEL")
+	setString(&cfg.LLM.BaseURL, "LLM_BASE_URL")
+	setString(&cfg.LLM.EmbeddingModel, "LLM_EMBEDDING_MODEL")
+
+	setString(&cfg.YouTube.APIKey, "YOUTUBE_API_KEY")
+
+	if err := setInt(&cfg.Ingestion.Workers, "INGESTION_WORKERS"); err != nil {
+		return err
+	}
+
+	if err := setDuration(&cfg.Streak.CheckInterval, "STREAK_CHECK_INTERVAL"); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.Streak.BatchSize, "STREAK_BATCH_SIZE"); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.Streak.FreezeEveryDays, "STREAK_FREEZE_EVERY_DAYS"); err != nil {
+		return err
+	}
+	return setInt(&cfg.Streak.MaxFreezes, "STREAK_MAX_FREEZES")
+}
+
+func setString(target *string, key string) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		*target = value
+	}
+}
+
+func setInt(target *int, key string) error {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s harus berupa angka: %w", key, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func setDuration(target *Duration, key string) error {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
 	if err != nil {
-		log.Fatal("Error loading .env file")
+		return fmt.Errorf("%s harus berupa durasi seperti 15m atau 24h: %w", key, err)
 	}
-}
\ No newline at end of file
+	*target = Duration(parsed)
+	return nil
+}
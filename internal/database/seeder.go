@@ -32,5 +32,24 @@ func Seed() {
 		log.Printf("User admin SUDAH ADA: %s", adminUser.Email)
 	}
 
+	seedBadges()
+
 	log.Println("Seeder selesai dijalankan.")
 }
+
+// seedBadges membuat badge bawaan jika belum ada. Badge yang sudah diubah
+// admin tidak ditimpa.
+func seedBadges() {
+	badges := []model.Badge{
+		{Code: "streak_7", Name: "Streak 7 Hari", Description: "Menyelesaikan semua task selama 7 hari berturut-turut.", Metric: model.BadgeMetricStreakDays, Threshold: 7, IsActive: true},
+		{Code: "tasks_100", Name: "100 Task Selesai", Description: "Menyelesaikan 100 task.", Metric: model.BadgeMetricTasksCompleted, Threshold: 100, IsActive: true},
+		{Code: "first_perfect_quiz", Name: "Kuis Sempurna Pertama", Description: "Mendapat skor 100 pada percobaan pertama sebuah kuis.", Metric: model.BadgeMetricPerfectQuizzes, Threshold: 1, IsActive: true},
+		{Code: "focus_10_hours", Name: "Fokus 10 Jam", Description: "Mengumpulkan total 10 jam sesi fokus.", Metric: model.BadgeMetricFocusMinutes, Threshold: 600, IsActive: true},
+	}
+
+	for _, badge := range badges {
+		if err := DB.Where(model.Badge{Code: badge.Code}).FirstOrCreate(&badge).Error; err != nil {
+			log.Printf("Gagal menjalankan seeder badge %s: %v", badge.Code, err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type BadgeRequest struct {
+	Code        string `json:"code" binding:"required,max=50"`
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+	Metric      string `json:"metric" binding:"required,oneof=streak_days tasks_completed quizzes_completed perfect_quizzes focus_minutes"`
+	Threshold   int    `json:"threshold" binding:"required,min=1"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+type BadgeResponse struct {
+	ID          uint   `json:"id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Metric      string `json:"metric"`
+	Threshold   int    `json:"threshold"`
+	IsActive    bool   `json:"is_active"`
+}
+
+type AchievementResponse struct {
+	BadgeResponse
+	Earned   bool       `json:"earned"`
+	EarnedAt *time.Time `json:"earned_at"`
+	Progress int        `json:"progress"`
+}
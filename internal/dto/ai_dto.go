@@ -1,10 +1,13 @@
 package dto
 
+import "time"
+
 type MaterialResponse struct {
-	ID         uint   `json:"id"`
-	Title      string `json:"title"`
-	SourceType string `json:"source_type"`
-	Source     string `json:"source"`
+	ID         uint      `json:"id"`
+	Title      string    `json:"title"`
+	SourceType string    `json:"source_type"`
+	Source     string    `json:"source"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type IngestYouTubeRequest struct {
@@ -14,28 +17,63 @@ type IngestYouTubeRequest struct {
 
 type GenerateSummaryRequest struct {
 	MaterialID uint `json:"material_id" binding:"required"`
+	Regenerate bool `json:"regenerate"`
 }
 
 type GenerateSummaryResponse struct {
-	MaterialID uint   `json:"material_id"`
-	Summary    string `json:"summary"`
+	MaterialID    uint      `json:"material_id"`
+	SummaryID     uint      `json:"summary_id"`
+	Version       int       `json:"version"`
+	PromptVersion string    `json:"prompt_version"`
+	Cached        bool      `json:"cached"`
+	ChunksCovered int       `json:"chunks_covered"`
+	Summary       string    `json:"summary"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
+// GenerateQuizRequest menerima jumlah soal pilihan ganda lewat QuestionCount,
+// atau komposisi per tipe lewat TypeMix (misalnya {"multiple_choice": 5,
+// "true_false": 3}). Jika keduanya diisi, QuestionCount harus sama dengan
+// total TypeMix.
 type GenerateQuizRequest struct {
-	MaterialID    uint `json:"material_id" binding:"required"`
-	QuestionCount int  `json:"question_count" binding:"required,min=1,max=10"`
+	MaterialID    uint           `json:"material_id" binding:"required"`
+	QuestionCount int            `json:"question_count" binding:"omitempty,min=1,max=20"`
+	Difficulty    string         `json:"difficulty" binding:"omitempty,oneof=easy medium hard"`
+	TypeMix       map[string]int `json:"type_mix" binding:"omitempty,dive,keys,oneof=multiple_choice true_false multiple_answer short_answer fill_blank,endkeys,min=0,max=20"`
 }
 
 type QuizOption map[string]string
 
+// QuizQuestion adalah bentuk soal yang diminta dari LLM. Pilihan hanya ada
+// untuk multiple_choice dan multiple_answer; JawabanAlternatif hanya untuk
+// short_answer dan fill_blank.
 type QuizQuestion struct {
-	ID           int          `json:"id"`
-	Pertanyaan   string       `json:"pertanyaan"`
-	Pilihan      []QuizOption `json:"pilihan"`
-	JawabanBenar string       `json:"jawaban_benar"`
+	ID                int          `json:"id"`
+	Tipe              string       `json:"tipe"`
+	Pertanyaan        string       `json:"pertanyaan"`
+	Pilihan           []QuizOption `json:"pilihan"`
+	JawabanBenar      string       `json:"jawaban_benar"`
+	JawabanAlternatif []string     `json:"jawaban_alternatif"`
+}
+
+// QuizQuestionResponse dibedakan lewat Tipe. Pilihan hanya dikirim untuk
+// multiple_choice dan multiple_answer; soal fill_blank menandai bagian
+// kosong dengan "___". Jawaban dikirim sebagai string: huruf ("B") untuk
+// multiple_choice, huruf dipisah koma ("A,C") untuk multiple_answer,
+// "benar"/"salah" untuk true_false, dan teks bebas untuk tipe lainnya.
+type QuizQuestionResponse struct {
+	ID         uint         `json:"id"`
+	Nomor      int          `json:"nomor"`
+	Tipe       string       `json:"tipe"`
+	Pertanyaan string       `json:"pertanyaan"`
+	Pilihan    []QuizOption `json:"pilihan,omitempty"`
 }
 
 type GenerateQuizResponse struct {
-	MaterialID uint           `json:"material_id"`
-	Questions  []QuizQuestion `json:"questions"`
+	MaterialID    uint                   `json:"material_id"`
+	QuizID        uint                   `json:"quiz_id"`
+	Difficulty    string                 `json:"difficulty"`
+	ChunksCovered int                    `json:"chunks_covered"`
+	TotalChunks   int                    `json:"total_chunks"`
+	Questions     []QuizQuestionResponse `json:"questions"`
 }
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+type GenerateFlashcardsRequest struct {
+	MaterialID uint `json:"material_id" binding:"required"`
+	CardCount  int  `json:"card_count" binding:"required,min=1,max=30"`
+}
+
+// FlashcardItem adalah format satu kartu dari LLM.
+type FlashcardItem struct {
+	Depan    string `json:"depan"`
+	Belakang string `json:"belakang"`
+}
+
+type ReviewFlashcardRequest struct {
+	// Grade mengikuti skala SM-2: 0 (lupa total) sampai 5 (ingat sempurna).
+	Grade *int `json:"grade" binding:"required,min=0,max=5"`
+}
+
+type FlashcardResponse struct {
+	ID             uint       `json:"id"`
+	DeckID         uint       `json:"deck_id"`
+	Front          string     `json:"front"`
+	Back           string     `json:"back"`
+	EaseFactor     float64    `json:"ease_factor"`
+	IntervalDays   int        `json:"interval_days"`
+	Repetitions    int        `json:"repetitions"`
+	DueDate        string     `json:"due_date"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+}
+
+type FlashcardDeckResponse struct {
+	ID            uint                `json:"id"`
+	MaterialID    uint                `json:"material_id"`
+	Title         string              `json:"title"`
+	ChunksCovered int                 `json:"chunks_covered"`
+	TotalChunks   int                 `json:"total_chunks"`
+	Cards         []FlashcardResponse `json:"cards"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+type DueFlashcardsResponse struct {
+	Date     string              `json:"date"`
+	DueCount int64               `json:"due_count"`
+	Cards    []FlashcardResponse `json:"cards"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type StartFocusRequest struct {
+	PlannedMinutes int   `json:"planned_minutes" binding:"required,min=1,max=480"`
+	TaskID         *uint `json:"task_id"`
+}
+
+type FocusSessionResponse struct {
+	ID             uint       `json:"id"`
+	TaskID         *uint      `json:"task_id"`
+	Status         string     `json:"status"`
+	PlannedMinutes int        `json:"planned_minutes"`
+	ActualMinutes  int        `json:"actual_minutes"`
+	StartedAt      time.Time  `json:"started_at"`
+	PausedAt       *time.Time `json:"paused_at"`
+	EndedAt        *time.Time `json:"ended_at"`
+}
+
+type FocusDayStat struct {
+	Date     string `json:"date"`
+	Minutes  int    `json:"minutes"`
+	Sessions int    `json:"sessions"`
+}
+
+type FocusStatsResponse struct {
+	Period         string         `json:"period"`
+	StartDate      string         `json:"start_date"`
+	EndDate        string         `json:"end_date"`
+	TotalMinutes   int            `json:"total_minutes"`
+	PlannedMinutes int            `json:"planned_minutes"`
+	SessionCount   int            `json:"session_count"`
+	Days           []FocusDayStat `json:"days"`
+}
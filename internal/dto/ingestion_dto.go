@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type IngestionJobResponse struct {
+	ID         uint       `json:"id"`
+	SourceType string     `json:"source_type"`
+	Title      string     `json:"title"`
+	Source     string     `json:"source"`
+	Status     string     `json:"status"`
+	Progress   int        `json:"progress"`
+	Error      string     `json:"error,omitempty"`
+	MaterialID *uint      `json:"material_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
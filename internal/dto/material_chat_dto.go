@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+type MaterialChatRequest struct {
+	Question string `json:"question" binding:"required,max=2000"`
+}
+
+type ChatCitationResponse struct {
+	Number       int    `json:"number"`
+	PassageID    uint   `json:"passage_id"`
+	Page         *int   `json:"page,omitempty"`
+	StartSeconds *int   `json:"start_seconds,omitempty"`
+	Timestamp    string `json:"timestamp,omitempty"`
+	Snippet      string `json:"snippet"`
+}
+
+type ChatMessageResponse struct {
+	ID        uint                   `json:"id"`
+	Role      string                 `json:"role"`
+	Content   string                 `json:"content"`
+	Citations []ChatCitationResponse `json:"citations"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type MaterialChatResponse struct {
+	ConversationID uint                `json:"conversation_id"`
+	Question       ChatMessageResponse `json:"question"`
+	Answer         ChatMessageResponse `json:"answer"`
+}
+
+type MaterialConversationResponse struct {
+	ConversationID uint                  `json:"conversation_id"`
+	MaterialID     uint                  `json:"material_id"`
+	Messages       []ChatMessageResponse `json:"messages"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+type MaterialListResponse struct {
+	Items []MaterialResponse `json:"items"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+	Total int64              `json:"total"`
+}
+
+type MaterialDetailResponse struct {
+	MaterialResponse
+	ExtractedText string `json:"extracted_text,omitempty"`
+	Excerpt       string `json:"excerpt,omitempty"`
+	TextLength    int    `json:"text_length"`
+}
+
+type UpdateMaterialRequest struct {
+	Title string `json:"title" binding:"required"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+type MentorCodeResponse struct {
+	KodePembimbing string `json:"kode_pembimbing"`
+}
+
+type LinkMentorRequest struct {
+	KodePembimbing string `json:"kode_pembimbing" binding:"required"`
+}
+
+type MentorInfoResponse struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type MentorStudentResponse struct {
+	ID                  uint   `json:"id"`
+	Username            string `json:"username"`
+	Email               string `json:"email"`
+	CurrentStreak       int    `json:"current_streak"`
+	TotalTasksToday     int    `json:"total_tasks_today"`
+	CompletedTasksToday int    `json:"completed_tasks_today"`
+}
+
+type AssignTaskRequest struct {
+	Title     string `json:"title" binding:"required"`
+	TaskDate  string `json:"task_date" binding:"required"`
+	StudentID *uint  `json:"student_id"`
+}
+
+type AssignedTaskResponse struct {
+	ID              uint      `json:"id"`
+	Title           string    `json:"title"`
+	IsCompleted     bool      `json:"is_completed"`
+	TaskDate        time.Time `json:"task_date"`
+	StudentID       uint      `json:"student_id"`
+	StudentUsername string    `json:"student_username"`
+}
+
+type AssignedTasksResponse struct {
+	TaskDate       string                 `json:"task_date"`
+	TotalTasks     int                    `json:"total_tasks"`
+	CompletedTasks int                    `json:"completed_tasks"`
+	Tasks          []AssignedTaskResponse `json:"tasks"`
+}
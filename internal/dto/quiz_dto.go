@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+type StartQuizAttemptResponse struct {
+	AttemptID  uint                   `json:"attempt_id"`
+	QuizID     uint                   `json:"quiz_id"`
+	MaterialID uint                   `json:"material_id"`
+	Difficulty string                 `json:"difficulty"`
+	StartedAt  time.Time              `json:"started_at"`
+	Questions  []QuizQuestionResponse `json:"questions"`
+}
+
+type SubmitQuizAnswer struct {
+	QuestionID uint   `json:"question_id" binding:"required"`
+	Jawaban    string `json:"jawaban"`
+}
+
+type SubmitQuizAttemptRequest struct {
+	Answers []SubmitQuizAnswer `json:"answers" binding:"required,dive"`
+}
+
+type QuizAnswerFeedback struct {
+	QuestionID        uint     `json:"question_id"`
+	Nomor             int      `json:"nomor"`
+	Tipe              string   `json:"tipe"`
+	Pertanyaan        string   `json:"pertanyaan"`
+	Jawaban           string   `json:"jawaban"`
+	JawabanBenar      string   `json:"jawaban_benar"`
+	JawabanAlternatif []string `json:"jawaban_alternatif,omitempty"`
+	IsCorrect         bool     `json:"is_correct"`
+}
+
+type QuizAttemptResultResponse struct {
+	AttemptID      uint                 `json:"attempt_id"`
+	QuizID         uint                 `json:"quiz_id"`
+	MaterialID     uint                 `json:"material_id"`
+	TotalQuestions int                  `json:"total_questions"`
+	CorrectAnswers int                  `json:"correct_answers"`
+	Score          int                  `json:"score"`
+	StartedAt      time.Time            `json:"started_at"`
+	SubmittedAt    *time.Time           `json:"submitted_at"`
+	Feedback       []QuizAnswerFeedback `json:"feedback"`
+}
+
+type QuizAttemptHistoryResponse struct {
+	AttemptID      uint       `json:"attempt_id"`
+	QuizID         uint       `json:"quiz_id"`
+	TotalQuestions int        `json:"total_questions"`
+	CorrectAnswers int        `json:"correct_answers"`
+	Score          int        `json:"score"`
+	StartedAt      time.Time  `json:"started_at"`
+	SubmittedAt    *time.Time `json:"submitted_at"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+type CreateRoomRequest struct {
+	Name         string `json:"name" binding:"required,max=100"`
+	FocusMinutes int    `json:"focus_minutes" binding:"omitempty,min=1,max=180"`
+	BreakMinutes int    `json:"break_minutes" binding:"omitempty,min=1,max=60"`
+}
+
+type UpdateRoomStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=focusing break"`
+}
+
+type RoomResponse struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	OwnerID      uint      `json:"owner_id"`
+	OwnerName    string    `json:"owner_name"`
+	FocusMinutes int       `json:"focus_minutes"`
+	BreakMinutes int       `json:"break_minutes"`
+	OnlineCount  int       `json:"online_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type RoomMemberState struct {
+	UserID   uint      `json:"user_id"`
+	Username string    `json:"username"`
+	Status   string    `json:"status"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type RoomTimerState struct {
+	Phase     string     `json:"phase"`
+	Cycle     int        `json:"cycle"`
+	StartedAt *time.Time `json:"started_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+}
+
+type RoomStateResponse struct {
+	RoomID     uint              `json:"room_id"`
+	Members    []RoomMemberState `json:"members"`
+	Timer      RoomTimerState    `json:"timer"`
+	ServerTime time.Time         `json:"server_time"`
+}
@@ -8,14 +8,62 @@ type CreateTaskRequest struct {
 }
 
 type TaskResponse struct {
-	ID          uint      `json:"id"`
-	Title       string    `json:"title"`
-	IsCompleted bool      `json:"is_completed"`
-	TaskDate    time.Time `json:"task_date"`
-	UserID      uint      `json:"user_id"`
+	ID           uint      `json:"id"`
+	Title        string    `json:"title"`
+	IsCompleted  bool      `json:"is_completed"`
+	TaskDate     time.Time `json:"task_date"`
+	UserID       uint      `json:"user_id"`
+	AssignedByID *uint     `json:"assigned_by_id"`
+	RecurrenceID *uint     `json:"recurrence_id"`
+
+	// Streak hanya diisi saat task ditandai selesai.
+	Streak *StreakStateResponse `json:"streak,omitempty"`
 }
 
 type UpdateTaskRequest struct {
 	Title       string `json:"title" binding:"required"`
 	IsCompleted bool   `json:"is_completed"`
 }
+
+type CreateRecurringTaskRequest struct {
+	Title     string `json:"title" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"`
+	Frequency string `json:"frequency" binding:"required,oneof=daily weekly interval"`
+	Interval  int    `json:"interval" binding:"omitempty,min=1,max=365"`
+	Weekdays  []int  `json:"weekdays" binding:"omitempty,dive,min=0,max=6"`
+	UntilDate string `json:"until_date"`
+	Count     *int   `json:"count" binding:"omitempty,min=1,max=1000"`
+}
+
+type TaskRecurrenceResponse struct {
+	ID            uint       `json:"id"`
+	Title         string     `json:"title"`
+	Frequency     string     `json:"frequency"`
+	Interval      int        `json:"interval"`
+	Weekdays      []int      `json:"weekdays"`
+	StartDate     time.Time  `json:"start_date"`
+	UntilDate     *time.Time `json:"until_date"`
+	Count         *int       `json:"count"`
+	ExcludedDates []string   `json:"excluded_dates"`
+}
+
+type TaskDayGroup struct {
+	Date  string         `json:"date"`
+	Tasks []TaskResponse `json:"tasks"`
+}
+
+type CalendarDay struct {
+	Date                string `json:"date"`
+	Total               int    `json:"total"`
+	Completed           int    `json:"completed"`
+	CountedTowardStreak bool   `json:"counted_toward_streak"`
+}
+
+type CalendarSummaryResponse struct {
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	TotalTasks     int           `json:"total_tasks"`
+	CompletedTasks int           `json:"completed_tasks"`
+	StreakDays     int           `json:"streak_days"`
+	Days           []CalendarDay `json:"days"`
+}
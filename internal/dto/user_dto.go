@@ -8,7 +8,12 @@ type UserResponse struct {
 	Email         string    `json:"email"`
 	Role          string    `json:"role"`
 	CurrentStreak int       `json:"current_streak"`
+	LongestStreak int       `json:"longest_streak"`
+	StreakFreezes int       `json:"streak_freezes"`
+	TimeZone      string    `json:"time_zone"`
 	CreatedAt     time.Time `json:"created_at"`
+
+	HideFromLeaderboard bool `json:"hide_from_leaderboard"`
 }
 
 type UpdateUserRequest struct {
@@ -16,3 +21,52 @@ type UpdateUserRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Role     string `json:"role" binding:"required,oneof=siswa pembimbing admin"`
 }
+
+type UpdateTimeZoneRequest struct {
+	TimeZone string `json:"time_zone" binding:"required"`
+}
+
+type UpdateLeaderboardVisibilityRequest struct {
+	HideFromLeaderboard *bool `json:"hide_from_leaderboard" binding:"required"`
+}
+
+type StreakJobRunResponse struct {
+	ID           uint       `json:"id"`
+	Trigger      string     `json:"trigger"`
+	Status       string     `json:"status"`
+	UsersScanned int        `json:"users_scanned"`
+	UsersChecked int        `json:"users_checked"`
+	UsersReset   int        `json:"users_reset"`
+	UsersFailed  int        `json:"users_failed"`
+	Error        string     `json:"error,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+}
+
+type StreakEventResponse struct {
+	ID           uint      `json:"id"`
+	Type         string    `json:"type"`
+	Day          string    `json:"day"`
+	StreakBefore int       `json:"streak_before"`
+	StreakAfter  int       `json:"streak_after"`
+	FreezesAfter int       `json:"freezes_after"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type StreakHistoryResponse struct {
+	CurrentStreak int                   `json:"current_streak"`
+	LongestStreak int                   `json:"longest_streak"`
+	StreakFreezes int                   `json:"streak_freezes"`
+	Events        []StreakEventResponse `json:"events"`
+	Page          int                   `json:"page"`
+	Limit         int                   `json:"limit"`
+	Total         int64                 `json:"total"`
+}
+
+// StreakStateResponse adalah state streak setelah sebuah task diselesaikan.
+type StreakStateResponse struct {
+	CurrentStreak int  `json:"current_streak"`
+	LongestStreak int  `json:"longest_streak"`
+	StreakFreezes int  `json:"streak_freezes"`
+	AwardedToday  bool `json:"awarded_today"`
+}
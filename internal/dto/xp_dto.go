@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type XPSummaryResponse struct {
+	TotalXP             int  `json:"total_xp"`
+	WeeklyXP            int  `json:"weekly_xp"`
+	Level               int  `json:"level"`
+	LevelXP             int  `json:"level_xp"`
+	NextLevelXP         int  `json:"next_level_xp"`
+	HideFromLeaderboard bool `json:"hide_from_leaderboard"`
+}
+
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	XP       int    `json:"xp"`
+}
+
+type LeaderboardResponse struct {
+	Period  string             `json:"period"`
+	Scope   string             `json:"scope"`
+	Since   *time.Time         `json:"since"`
+	Entries []LeaderboardEntry `json:"entries"`
+	Me      *LeaderboardEntry  `json:"me"`
+}
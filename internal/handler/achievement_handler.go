@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type AchievementHandler struct {
+	service *service.AchievementService
+}
+
+func NewAchievementHandler(s *service.AchievementService) *AchievementHandler {
+	return &AchievementHandler{service: s}
+}
+
+func (h *AchievementHandler) GetMyAchievements(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetMyAchievements(userIDString.(string))
+	if err != nil {
+		handleAchievementError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil achievement", http.StatusOK)
+}
+
+func (h *AchievementHandler) GetBadges(c *gin.Context) {
+	response, err := h.service.GetBadges()
+	if err != nil {
+		handleAchievementError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil data badge", http.StatusOK)
+}
+
+func (h *AchievementHandler) CreateBadge(c *gin.Context) {
+	var req dto.BadgeRequest
+	if !bindBadgeRequest(c, &req) {
+		return
+	}
+
+	response, err := h.service.CreateBadge(req)
+	if err != nil {
+		handleAchievementError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Badge berhasil dibuat", http.StatusCreated)
+}
+
+func (h *AchievementHandler) UpdateBadge(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.Error(c.Writer, nil, "ID badge tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.BadgeRequest
+	if !bindBadgeRequest(c, &req) {
+		return
+	}
+
+	response, err := h.service.UpdateBadge(uint(id), req)
+	if err != nil {
+		handleAchievementError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Badge berhasil diupdate", http.StatusOK)
+}
+
+func (h *AchievementHandler) DeleteBadge(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.Error(c.Writer, nil, "ID badge tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteBadge(uint(id)); err != nil {
+		handleAchievementError(c, err)
+		return
+	}
+	utils.Success(c.Writer, nil, "Badge berhasil dihapus", http.StatusOK)
+}
+
+func bindBadgeRequest(c *gin.Context, req *dto.BadgeRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return false
+	}
+	return true
+}
+
+func handleAchievementError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user ID tidak valid":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+	case "badge tidak ditemukan":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+	case "kode badge sudah dipakai":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusConflict)
+	default:
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -19,86 +20,123 @@ func NewAIHandler(s *service.AIService) *AIHandler {
 	return &AIHandler{service: s}
 }
 
-func (h *AIHandler) IngestPDF(c *gin.Context) {
+func (h *AIHandler) GenerateSummary(c *gin.Context) {
 	userIDString, _ := c.Get("user_id")
+	var req dto.GenerateSummaryRequest
 
-	file, err := c.FormFile("pdf")
-	if err != nil {
-		utils.Error(c.Writer, nil, "File 'pdf' tidak ditemukan", http.StatusBadRequest)
+	if err := c.ShouldBindJSON(&req); err != nil {
 		return
 	}
-	title := c.PostForm("title")
-	if title == "" {
-		title = file.Filename
-	}
 
-	response, err := h.service.IngestPDF(c.Request.Context(), file, title, userIDString.(string))
+	response, err := h.service.GenerateSummary(c.Request.Context(), req, userIDString.(string))
 	if err != nil {
+		if err.Error() == "materi tidak ditemukan atau anda tidak punya akses" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+			return
+		}
 		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	utils.Success(c.Writer, response, "Materi PDF berhasil disimpan", http.StatusCreated)
+	utils.Success(c.Writer, response, "Rangkuman berhasil dibuat", http.StatusOK)
 }
 
-func (h *AIHandler) IngestYouTube(c *gin.Context) {
+// StreamSummary adalah versi SSE dari GenerateSummary. Event "delta" berisi
+// potongan teks rangkuman, "done" berisi rangkuman lengkap yang sudah
+// disimpan, dan "error" dikirim jika pembuatan gagal setelah stream dimulai.
+// Error sebelum potongan pertama tetap dikembalikan sebagai JSON biasa.
+func (h *AIHandler) StreamSummary(c *gin.Context) {
 	userIDString, _ := c.Get("user_id")
-	var req dto.IngestYouTubeRequest
+	var req dto.GenerateSummaryRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		var validationErrs validator.ValidationErrors
 		if errors.As(err, &validationErrs) {
 			formattedErrors := utils.FormatValidationError(validationErrs)
-			utils.Error(c.Writer, formattedErrors, "Data tidak valid", http.StatusBadRequest)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
 		} else {
 			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
 		}
 		return
 	}
 
-	response, err := h.service.IngestYouTube(c.Request.Context(), req, userIDString.(string))
+	ctx := c.Request.Context()
+	streaming := false
+	send := func(event string, data interface{}) {
+		if !streaming {
+			streaming = true
+			c.Header("Cache-Control", "no-cache")
+			c.Header("Connection", "keep-alive")
+			c.Header("X-Accel-Buffering", "no")
+		}
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+
+	response, err := h.service.StreamSummary(ctx, req, userIDString.(string), func(chunk string) error {
+		send("delta", gin.H{"text": chunk})
+		return ctx.Err()
+	})
 	if err != nil {
-		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		switch {
+		case ctx.Err() != nil:
+			log.Printf("[Summary] Client menutup stream rangkuman materi %d.", req.MaterialID)
+		case streaming:
+			send("error", gin.H{"message": err.Error()})
+		case err.Error() == "materi tidak ditemukan atau anda tidak punya akses":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
-	utils.Success(c.Writer, response, "Materi YouTube berhasil disimpan", http.StatusCreated)
+	send("done", response)
 }
 
-func (h *AIHandler) GenerateSummary(c *gin.Context) {
+func (h *AIHandler) GenerateQuiz(c *gin.Context) {
 	userIDString, _ := c.Get("user_id")
-	var req dto.GenerateSummaryRequest
+	var req dto.GenerateQuizRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
 		return
 	}
 
-	response, err := h.service.GenerateSummary(c.Request.Context(), req, userIDString.(string))
+	response, err := h.service.GenerateQuiz(c.Request.Context(), req, userIDString.(string))
 	if err != nil {
-		if err.Error() == "materi tidak ditemukan atau anda tidak punya akses" {
+		switch {
+		case errors.Is(err, service.ErrQuizCountRequired), errors.Is(err, service.ErrQuizMixEmpty),
+			errors.Is(err, service.ErrQuizCountMismatch), errors.Is(err, service.ErrQuizTooManyQuestions):
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case err.Error() == "materi tidak ditemukan atau anda tidak punya akses":
 			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
-			return
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
 		}
-		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	utils.Success(c.Writer, response, "Rangkuman berhasil dibuat", http.StatusOK)
+	utils.Success(c.Writer, response, "Kuis berhasil dibuat", http.StatusOK)
 }
 
-func (h *AIHandler) GenerateQuiz(c *gin.Context) {
+func (h *AIHandler) GetSummaries(c *gin.Context) {
 	userIDString, _ := c.Get("user_id")
-	var req dto.GenerateQuizRequest
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		return
-	}
 
-	response, err := h.service.GenerateQuiz(c.Request.Context(), req, userIDString.(string))
+	response, err := h.service.GetSummaries(c.Query("material_id"), userIDString.(string))
 	if err != nil {
-		if err.Error() == "materi tidak ditemukan atau anda tidak punya akses" {
+		switch err.Error() {
+		case "material ID tidak valid":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case "materi tidak ditemukan atau anda tidak punya akses":
 			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
-			return
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
 		}
-		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	utils.Success(c.Writer, response, "Kuis berhasil dibuat", http.StatusOK)
+	utils.Success(c.Writer, response, "Berhasil mengambil riwayat rangkuman", http.StatusOK)
 }
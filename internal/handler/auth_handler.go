@@ -44,6 +44,10 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			utils.Error(c.Writer, nil, err.Error(), http.StatusConflict)
 			return
 		}
+		if err.Error() == "zona waktu tidak valid" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+			return
+		}
 		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -78,3 +82,73 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	utils.Success(c.Writer, response, "Login berhasil", http.StatusOK)
 }
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.Refresh(req)
+	if err != nil {
+		if err.Error() == "refresh token tidak valid" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	utils.Success(c.Writer, response, "Token berhasil diperbarui", http.StatusOK)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	if err := h.service.Logout(req); err != nil {
+		if err.Error() == "refresh token tidak valid" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	utils.Success(c.Writer, nil, "Logout berhasil", http.StatusOK)
+}
+
+func (h *AuthHandler) IssueStreamToken(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.IssueStreamToken(userIDString.(string))
+	if err != nil {
+		switch err.Error() {
+		case "user tidak ditemukan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	utils.Success(c.Writer, response, "Stream token berhasil dibuat", http.StatusOK)
+}
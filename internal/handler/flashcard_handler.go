@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type FlashcardHandler struct {
+	service *service.FlashcardService
+}
+
+func NewFlashcardHandler(s *service.FlashcardService) *FlashcardHandler {
+	return &FlashcardHandler{service: s}
+}
+
+func (h *FlashcardHandler) GenerateDeck(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.GenerateFlashcardsRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.GenerateDeck(c.Request.Context(), req, userIDString.(string))
+	if err != nil {
+		handleFlashcardError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Flashcard berhasil dibuat", http.StatusCreated)
+}
+
+func (h *FlashcardHandler) GetDueCards(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetDueCards(userIDString.(string), c.Query("limit"))
+	if err != nil {
+		handleFlashcardError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil flashcard jatuh tempo", http.StatusOK)
+}
+
+func (h *FlashcardHandler) ReviewCard(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.ReviewFlashcardRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.ReviewCard(c.Param("id"), userIDString.(string), req)
+	if err != nil {
+		handleFlashcardError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Review flashcard tersimpan", http.StatusOK)
+}
+
+func handleFlashcardError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user ID tidak valid", "flashcard ID tidak valid", "parameter limit tidak valid":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+	case "materi tidak ditemukan atau anda tidak punya akses", "flashcard tidak ditemukan", "user tidak ditemukan":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+	case "flashcard belum jatuh tempo":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusConflict)
+	default:
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+	}
+}
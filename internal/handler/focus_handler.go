@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type FocusHandler struct {
+	service *service.FocusService
+}
+
+func NewFocusHandler(s *service.FocusService) *FocusHandler {
+	return &FocusHandler{service: s}
+}
+
+func (h *FocusHandler) StartSession(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.StartFocusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.StartSession(userIDString.(string), req)
+	if err != nil {
+		handleFocusError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Sesi fokus dimulai", http.StatusCreated)
+}
+
+func (h *FocusHandler) PauseSession(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.PauseSession(c.Param("id"), userIDString.(string))
+	if err != nil {
+		handleFocusError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Sesi fokus dijeda", http.StatusOK)
+}
+
+func (h *FocusHandler) ResumeSession(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.ResumeSession(c.Param("id"), userIDString.(string))
+	if err != nil {
+		handleFocusError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Sesi fokus dilanjutkan", http.StatusOK)
+}
+
+func (h *FocusHandler) StopSession(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.StopSession(c.Param("id"), userIDString.(string))
+	if err != nil {
+		handleFocusError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Sesi fokus selesai", http.StatusOK)
+}
+
+func (h *FocusHandler) GetActiveSession(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetActiveSession(userIDString.(string))
+	if err != nil {
+		handleFocusError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil sesi fokus aktif", http.StatusOK)
+}
+
+func (h *FocusHandler) GetSessions(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetSessions(userIDString.(string), c.Query("from"), c.Query("to"))
+	if err != nil {
+		handleFocusError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil riwayat sesi fokus", http.StatusOK)
+}
+
+func (h *FocusHandler) GetStats(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetStats(userIDString.(string), c.Query("period"), c.Query("date"))
+	if err != nil {
+		handleFocusError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil statistik fokus", http.StatusOK)
+}
+
+func handleFocusError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "sesi fokus tidak ditemukan", "task tidak ditemukan", "user tidak ditemukan":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+	case "masih ada sesi fokus yang aktif", "sesi fokus tidak sedang berjalan",
+		"sesi fokus tidak sedang dijeda", "sesi fokus sudah selesai":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusConflict)
+	case "gagal mengambil data sesi fokus", "gagal memulai sesi fokus", "gagal menyimpan sesi fokus":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+	default:
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+	}
+}
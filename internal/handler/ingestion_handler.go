@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type IngestionHandler struct {
+	service *service.IngestionService
+}
+
+func NewIngestionHandler(s *service.IngestionService) *IngestionHandler {
+	return &IngestionHandler{service: s}
+}
+
+func (h *IngestionHandler) IngestPDF(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	file, err := c.FormFile("pdf")
+	if err != nil {
+		utils.Error(c.Writer, nil, "File 'pdf' tidak ditemukan", http.StatusBadRequest)
+		return
+	}
+	title := c.PostForm("title")
+	if title == "" {
+		title = file.Filename
+	}
+
+	response, err := h.service.EnqueuePDF(file, title, userIDString.(string))
+	if err != nil {
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Success(c.Writer, response, "Materi PDF sedang diproses", http.StatusAccepted)
+}
+
+func (h *IngestionHandler) IngestYouTube(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.IngestYouTubeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.EnqueueYouTube(req, userIDString.(string))
+	if err != nil {
+		if err.Error() == "ingest YouTube belum dikonfigurasi" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Success(c.Writer, response, "Materi YouTube sedang diproses", http.StatusAccepted)
+}
+
+func (h *IngestionHandler) GetJob(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetJob(c.Param("id"), userIDString.(string))
+	if err != nil {
+		switch err.Error() {
+		case "job ID tidak valid":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case "job tidak ditemukan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil status job", http.StatusOK)
+}
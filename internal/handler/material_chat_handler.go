@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type MaterialChatHandler struct {
+	service *service.MaterialChatService
+}
+
+func NewMaterialChatHandler(s *service.MaterialChatService) *MaterialChatHandler {
+	return &MaterialChatHandler{service: s}
+}
+
+func (h *MaterialChatHandler) Ask(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.MaterialChatRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.Ask(c.Request.Context(), c.Param("id"), userIDString.(string), req)
+	if err != nil {
+		handleMaterialChatError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil menjawab pertanyaan", http.StatusOK)
+}
+
+func (h *MaterialChatHandler) GetHistory(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetHistory(c.Param("id"), userIDString.(string))
+	if err != nil {
+		handleMaterialChatError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil riwayat chat", http.StatusOK)
+}
+
+func (h *MaterialChatHandler) ClearHistory(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	if err := h.service.ClearHistory(c.Param("id"), userIDString.(string)); err != nil {
+		handleMaterialChatError(c, err)
+		return
+	}
+	utils.Success(c.Writer, nil, "Riwayat chat berhasil dihapus", http.StatusOK)
+}
+
+func handleMaterialChatError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user ID tidak valid", "material ID tidak valid", "materi tidak memiliki teks untuk diproses":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+	case "materi tidak ditemukan atau anda tidak punya akses":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+	default:
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type MaterialHandler struct {
+	service *service.MaterialService
+}
+
+func NewMaterialHandler(s *service.MaterialService) *MaterialHandler {
+	return &MaterialHandler{service: s}
+}
+
+func (h *MaterialHandler) GetMaterials(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetMaterials(
+		userIDString.(string),
+		c.Query("source_type"),
+		c.Query("search"),
+		c.Query("page"),
+		c.Query("limit"),
+	)
+	if err != nil {
+		if err.Error() == "gagal mengambil data materi" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil daftar materi", http.StatusOK)
+}
+
+func (h *MaterialHandler) GetMaterial(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetMaterial(c.Param("id"), userIDString.(string), c.Query("excerpt"))
+	if err != nil {
+		handleMaterialError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil detail materi", http.StatusOK)
+}
+
+func (h *MaterialHandler) RenameMaterial(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.UpdateMaterialRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.RenameMaterial(c.Param("id"), userIDString.(string), req)
+	if err != nil {
+		handleMaterialError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Materi berhasil diupdate", http.StatusOK)
+}
+
+func (h *MaterialHandler) DeleteMaterial(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	if err := h.service.DeleteMaterial(c.Param("id"), userIDString.(string)); err != nil {
+		handleMaterialError(c, err)
+		return
+	}
+	utils.Success(c.Writer, nil, "Materi berhasil dihapus", http.StatusOK)
+}
+
+func handleMaterialError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "material ID tidak valid", "parameter excerpt tidak valid":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+	case "materi tidak ditemukan atau anda tidak punya akses":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+	default:
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type MentorHandler struct {
+	service *service.MentorService
+}
+
+func NewMentorHandler(s *service.MentorService) *MentorHandler {
+	return &MentorHandler{service: s}
+}
+
+func (h *MentorHandler) GetCode(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetCode(userIDString.(string))
+	if err != nil {
+		switch err.Error() {
+		case "kode pembimbing belum dibuat", "user tidak ditemukan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil kode pembimbing", http.StatusOK)
+}
+
+func (h *MentorHandler) GenerateCode(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GenerateCode(userIDString.(string))
+	if err != nil {
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Success(c.Writer, response, "Kode pembimbing berhasil dibuat", http.StatusCreated)
+}
+
+func (h *MentorHandler) GetStudents(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetStudents(userIDString.(string))
+	if err != nil {
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil data siswa bimbingan", http.StatusOK)
+}
+
+func (h *MentorHandler) LinkStudent(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.LinkMentorRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.LinkStudent(userIDString.(string), req)
+	if err != nil {
+		if err.Error() == "kode pembimbing tidak ditemukan" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+			return
+		}
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil terhubung dengan pembimbing", http.StatusOK)
+}
+
+func (h *MentorHandler) AssignTask(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.AssignTaskRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.AssignTask(userIDString.(string), req)
+	if err != nil {
+		switch err.Error() {
+		case "format tanggal tidak valid, gunakan YYYY-MM-DD", "belum ada siswa yang terhubung":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case "siswa tidak ditemukan atau bukan bimbingan anda":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.Success(c.Writer, response, "Task berhasil diberikan ke siswa", http.StatusCreated)
+}
+
+func (h *MentorHandler) GetAssignedTasks(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetAssignedTasks(userIDString.(string), c.Query("date"))
+	if err != nil {
+		if err.Error() == "format tanggal tidak valid, gunakan YYYY-MM-DD" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+			return
+		}
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil task siswa bimbingan", http.StatusOK)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type QuizHandler struct {
+	service *service.QuizService
+}
+
+func NewQuizHandler(s *service.QuizService) *QuizHandler {
+	return &QuizHandler{service: s}
+}
+
+func (h *QuizHandler) StartAttempt(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.StartAttempt(c.Param("id"), userIDString.(string))
+	if err != nil {
+		switch err.Error() {
+		case "quiz ID tidak valid":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case "kuis tidak ditemukan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.Success(c.Writer, response, "Kuis berhasil dimulai", http.StatusCreated)
+}
+
+func (h *QuizHandler) SubmitAttempt(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.SubmitQuizAttemptRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.SubmitAttempt(c.Param("id"), userIDString.(string), req)
+	if err != nil {
+		switch err.Error() {
+		case "attempt ID tidak valid":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case "attempt kuis tidak ditemukan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		case "attempt kuis sudah dikumpulkan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusConflict)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.Success(c.Writer, response, "Jawaban kuis berhasil dinilai", http.StatusOK)
+}
+
+func (h *QuizHandler) GetAttempt(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetAttempt(c.Param("id"), userIDString.(string))
+	if err != nil {
+		switch err.Error() {
+		case "attempt ID tidak valid", "attempt kuis belum dikumpulkan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case "attempt kuis tidak ditemukan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil hasil kuis", http.StatusOK)
+}
+
+func (h *QuizHandler) GetAttemptHistory(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetAttemptHistory(c.Query("material_id"), userIDString.(string))
+	if err != nil {
+		if err.Error() == "material ID tidak valid" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+			return
+		}
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil riwayat kuis", http.StatusOK)
+}
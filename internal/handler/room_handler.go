@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+// roomHeartbeatInterval menjaga koneksi SSE tetap hidup di balik proxy dan
+// membuat koneksi yang sudah putus cepat terdeteksi saat write gagal.
+const roomHeartbeatInterval = 25 * time.Second
+
+type RoomHandler struct {
+	service *service.RoomService
+}
+
+func NewRoomHandler(s *service.RoomService) *RoomHandler {
+	return &RoomHandler{service: s}
+}
+
+func (h *RoomHandler) CreateRoom(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.CreateRoomRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response, err := h.service.CreateRoom(userIDString.(string), req)
+	if err != nil {
+		handleRoomError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Room berhasil dibuat", http.StatusCreated)
+}
+
+func (h *RoomHandler) GetRooms(c *gin.Context) {
+	response, err := h.service.GetRooms()
+	if err != nil {
+		handleRoomError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil daftar room", http.StatusOK)
+}
+
+func (h *RoomHandler) GetRoomState(c *gin.Context) {
+	response, err := h.service.GetRoomState(c.Param("id"))
+	if err != nil {
+		handleRoomError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil state room", http.StatusOK)
+}
+
+func (h *RoomHandler) DeleteRoom(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	if err := h.service.DeleteRoom(c.Param("id"), userIDString.(string)); err != nil {
+		handleRoomError(c, err)
+		return
+	}
+	utils.Success(c.Writer, nil, "Room berhasil dihapus", http.StatusOK)
+}
+
+// Stream membuka koneksi SSE ke room. Selama koneksi terbuka user dihitung
+// hadir di room; presence dilepas otomatis saat client menutup koneksi.
+func (h *RoomHandler) Stream(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	sub, err := h.service.Join(c.Param("id"), userIDString.(string))
+	if err != nil {
+		handleRoomError(c, err)
+		return
+	}
+	defer h.service.Leave(sub)
+
+	heartbeat := time.NewTicker(roomHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-sub.Events:
+			if !ok {
+				c.SSEvent("closed", gin.H{"room_id": sub.RoomID})
+				return false
+			}
+			c.SSEvent(event.Name, event.Data)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
+
+func (h *RoomHandler) UpdateStatus(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+	var req dto.UpdateRoomStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	if err := h.service.UpdateStatus(c.Param("id"), userIDString.(string), req); err != nil {
+		handleRoomError(c, err)
+		return
+	}
+	utils.Success(c.Writer, nil, "Status berhasil diupdate", http.StatusOK)
+}
+
+func (h *RoomHandler) StartTimer(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	if err := h.service.StartTimer(c.Param("id"), userIDString.(string)); err != nil {
+		handleRoomError(c, err)
+		return
+	}
+	utils.Success(c.Writer, nil, "Timer room dimulai", http.StatusOK)
+}
+
+func (h *RoomHandler) StopTimer(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	if err := h.service.StopTimer(c.Param("id"), userIDString.(string)); err != nil {
+		handleRoomError(c, err)
+		return
+	}
+	utils.Success(c.Writer, nil, "Timer room dihentikan", http.StatusOK)
+}
+
+func handleRoomError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user ID tidak valid", "room ID tidak valid":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+	case "room tidak ditemukan", "user tidak ditemukan":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+	case "akses ditolak: hanya pembuat room yang bisa menghapus":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusForbidden)
+	case "anda belum bergabung di room ini":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusConflict)
+	default:
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type StreakHandler struct {
+	scheduler *service.StreakScheduler
+}
+
+func NewStreakHandler(scheduler *service.StreakScheduler) *StreakHandler {
+	return &StreakHandler{scheduler: scheduler}
+}
+
+func (h *StreakHandler) TriggerRun(c *gin.Context) {
+	h.scheduler.TriggerNow()
+	utils.Success(c.Writer, nil, "Job evaluasi streak dijalankan", http.StatusAccepted)
+}
+
+func (h *StreakHandler) GetRuns(c *gin.Context) {
+	response, err := h.scheduler.GetRuns(c.Query("limit"))
+	if err != nil {
+		if err.Error() == "parameter limit tidak valid" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+			return
+		}
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil riwayat job streak", http.StatusOK)
+}
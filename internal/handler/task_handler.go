@@ -77,8 +77,12 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	}
 
 	// 4. Panggil Service
-	response, err := h.service.UpdateTask(taskIDString, userIDString.(string), req)
+	response, err := h.service.UpdateTask(taskIDString, userIDString.(string), c.Query("scope"), req)
 	if err != nil {
+		if err.Error() == "scope harus this atau future" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err.Error() == "task tidak ditemukan" {
 			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound) // 404
 			return
@@ -103,8 +107,12 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	err := h.service.DeleteTask(taskIDString, userIDString.(string))
+	err := h.service.DeleteTask(taskIDString, userIDString.(string), c.Query("scope"))
 	if err != nil {
+		if err.Error() == "task ID tidak valid" || err.Error() == "scope harus this atau future" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err.Error() == "task tidak ditemukan" {
 			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
 			return
@@ -113,6 +121,10 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 			utils.Error(c.Writer, nil, err.Error(), http.StatusForbidden)
 			return
 		}
+		if err.Error() == "akses ditolak: task dari pembimbing tidak bisa dihapus" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusForbidden)
+			return
+		}
 		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -123,6 +135,17 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 func (h *TaskHandler) GetTasks(c *gin.Context) {
 	userIDString, _ := c.Get("user_id")
 
+	fromQuery, toQuery := c.Query("from"), c.Query("to")
+	if fromQuery != "" || toQuery != "" {
+		groups, err := h.service.GetTasksByRange(userIDString.(string), fromQuery, toQuery)
+		if err != nil {
+			handleTaskRangeError(c, err)
+			return
+		}
+		utils.Success(c.Writer, groups, "Berhasil mengambil tasks dari "+fromQuery+" sampai "+toQuery, http.StatusOK)
+		return
+	}
+
 	dateQuery := c.Query("date")
 
 	response, err := h.service.GetTasks(userIDString.(string), dateQuery)
@@ -141,3 +164,65 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 	}
 	utils.Success(c.Writer, response, message, http.StatusOK)
 }
+
+func (h *TaskHandler) CreateRecurringTask(c *gin.Context) {
+	var req dto.CreateRecurringTaskRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.CreateRecurringTask(req, userIDString.(string))
+	if err != nil {
+		if err.Error() == "gagal menyimpan task berulang ke database" {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	utils.Success(c.Writer, response, "Task berulang berhasil ditambahkan", http.StatusCreated)
+}
+
+func (h *TaskHandler) GetRecurringTasks(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetRecurringTasks(userIDString.(string))
+	if err != nil {
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil daftar task berulang", http.StatusOK)
+}
+
+func (h *TaskHandler) GetCalendarSummary(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetCalendarSummary(userIDString.(string), c.Query("from"), c.Query("to"))
+	if err != nil {
+		handleTaskRangeError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil ringkasan kalender", http.StatusOK)
+}
+
+func handleTaskRangeError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user tidak ditemukan":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+	case "gagal menyiapkan task berulang", "gagal mengambil data task", "gagal mengambil riwayat streak":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+	default:
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+	}
+}
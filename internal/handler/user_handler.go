@@ -40,6 +40,66 @@ func (h *UserHandler) GetSelf(c *gin.Context) {
 	utils.Success(c.Writer, user, "Berhasil mengambil data profil", http.StatusOK)
 }
 
+func (h *UserHandler) UpdateTimeZone(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	var req dto.UpdateTimeZoneRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	user, err := h.service.UpdateTimeZone(userID.(string), req)
+	if err != nil {
+		switch err.Error() {
+		case "zona waktu tidak valid", "user ID tidak valid":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case "user tidak ditemukan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.Success(c.Writer, user, "Zona waktu berhasil diupdate", http.StatusOK)
+}
+
+func (h *UserHandler) UpdateLeaderboardVisibility(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	var req dto.UpdateLeaderboardVisibilityRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			formattedErrors := utils.FormatValidationError(validationErrs)
+			utils.Error(c.Writer, formattedErrors, "Data yang diberikan tidak valid", http.StatusBadRequest)
+		} else {
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	user, err := h.service.UpdateLeaderboardVisibility(userID.(string), req)
+	if err != nil {
+		switch err.Error() {
+		case "user ID tidak valid":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case "user tidak ditemukan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.Success(c.Writer, user, "Pengaturan leaderboard berhasil diupdate", http.StatusOK)
+}
+
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -109,6 +169,24 @@ func (h *UserHandler) CheckAndUpdateStreak(c *gin.Context) {
 
 	utils.Success(c.Writer, response, "Streak berhasil dicek dan diupdate", http.StatusOK)
 }
+func (h *UserHandler) GetStreakHistory(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	response, err := h.service.GetStreakHistory(userID.(string), c.Query("page"), c.Query("limit"))
+	if err != nil {
+		switch err.Error() {
+		case "parameter page tidak valid", "parameter limit tidak valid", "user ID tidak valid":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+		case "user tidak ditemukan":
+			utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+		default:
+			utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil riwayat streak", http.StatusOK)
+}
+
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
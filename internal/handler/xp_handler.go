@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+type XPHandler struct {
+	service *service.XPService
+}
+
+func NewXPHandler(s *service.XPService) *XPHandler {
+	return &XPHandler{service: s}
+}
+
+func (h *XPHandler) GetMyXP(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetMyXP(userIDString.(string))
+	if err != nil {
+		handleXPError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil data XP", http.StatusOK)
+}
+
+// GetLeaderboard menerima query period (weekly, all_time), scope (global,
+// mentor, room), room_id untuk scope room, dan limit.
+func (h *XPHandler) GetLeaderboard(c *gin.Context) {
+	userIDString, _ := c.Get("user_id")
+
+	response, err := h.service.GetLeaderboard(userIDString.(string), c.Query("period"), c.Query("scope"), c.Query("room_id"), c.Query("limit"))
+	if err != nil {
+		handleXPError(c, err)
+		return
+	}
+	utils.Success(c.Writer, response, "Berhasil mengambil leaderboard", http.StatusOK)
+}
+
+func handleXPError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user tidak ditemukan", "room tidak ditemukan":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusNotFound)
+	case "gagal mengambil data XP", "gagal mengambil leaderboard", "gagal mengambil data room":
+		utils.Error(c.Writer, nil, err.Error(), http.StatusInternalServerError)
+	default:
+		utils.Error(c.Writer, nil, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -5,10 +5,11 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mohamadarif03/focus-room-be/internal/service"
 	"github.com/mohamadarif03/focus-room-be/pkg/utils"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -32,6 +33,44 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if err := authService.CheckTokenVersion(claims.UserID, claims.TokenVersion); err != nil {
+			utils.Error(c.Writer, nil, "Token sudah tidak berlaku, silakan login ulang", http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("role", claims.Role)
+
+		c.Next()
+	}
+}
+
+// StreamTokenMiddleware mengautentikasi koneksi SSE lewat query ?token=,
+// karena EventSource tidak bisa mengirim header Authorization. Hanya token
+// dari AuthService.IssueStreamToken yang diterima, bukan access token biasa.
+func StreamTokenMiddleware(authService *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.Query("token")
+		if tokenString == "" {
+			utils.Error(c.Writer, nil, "Parameter token dibutuhkan", http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		claims, err := utils.ValidateStreamToken(tokenString)
+		if err != nil {
+			utils.Error(c.Writer, nil, "Token tidak valid atau expired", http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		if err := authService.CheckTokenVersion(claims.UserID, claims.TokenVersion); err != nil {
+			utils.Error(c.Writer, nil, "Token sudah tidak berlaku, silakan login ulang", http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
 
@@ -90,3 +129,28 @@ func StudentMiddleware() gin.HandlerFunc {
 
 	}
 }
+
+func MentorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roleValue, exists := c.Get("role")
+		if !exists {
+			utils.Error(c.Writer, nil, "Gagal mengambil role dari context", http.StatusInternalServerError)
+			c.Abort()
+			return
+		}
+
+		role, ok := roleValue.(string)
+		if !ok {
+			utils.Error(c.Writer, nil, "Role di context formatnya salah", http.StatusInternalServerError)
+			c.Abort()
+			return
+		}
+		if role != "pembimbing" {
+			utils.Error(c.Writer, nil, "Akses ditolak. Hanya untuk pembimbing.", http.StatusForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
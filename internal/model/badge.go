@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// Metrik yang bisa dipakai sebagai syarat badge. Metrik kuis hanya
+// menghitung attempt pertama tiap kuis.
+const (
+	BadgeMetricStreakDays       = "streak_days"
+	BadgeMetricTasksCompleted   = "tasks_completed"
+	BadgeMetricQuizzesCompleted = "quizzes_completed"
+	BadgeMetricPerfectQuizzes   = "perfect_quizzes"
+	BadgeMetricFocusMinutes     = "focus_minutes"
+)
+
+// Badge adalah definisi achievement. User mendapat badge saat nilai Metric
+// miliknya mencapai Threshold.
+type Badge struct {
+	ID          uint   `gorm:"primaryKey"`
+	Code        string `gorm:"size:50;not null;unique"`
+	Name        string `gorm:"size:100;not null"`
+	Description string `gorm:"type:text"`
+	Metric      string `gorm:"size:30;not null;index"`
+	Threshold   int    `gorm:"not null"`
+	IsActive    bool   `gorm:"not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type UserBadge struct {
+	ID       uint      `gorm:"primaryKey"`
+	UserID   uint      `gorm:"not null;uniqueIndex:idx_user_badge,priority:1"`
+	BadgeID  uint      `gorm:"not null;uniqueIndex:idx_user_badge,priority:2"`
+	EarnedAt time.Time `gorm:"not null"`
+
+	User  User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Badge Badge `gorm:"foreignKey:BadgeID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+)
+
+type FlashcardDeck struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	MaterialID uint   `gorm:"not null;index"`
+	Title      string `gorm:"size:255;not null"`
+	CreatedAt  time.Time
+
+	Cards    []Flashcard `gorm:"foreignKey:DeckID;constraint:OnDelete:CASCADE"`
+	User     User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Material Material    `gorm:"foreignKey:MaterialID;constraint:OnDelete:CASCADE"`
+}
+
+// Flashcard menyimpan state SM-2 per kartu. DueDate adalah tanggal lokal
+// user saat kartu perlu diulang lagi.
+type Flashcard struct {
+	ID             uint       `gorm:"primaryKey"`
+	DeckID         uint       `gorm:"not null;index"`
+	UserID         uint       `gorm:"not null;index:idx_flashcard_user_due,priority:1"`
+	Front          string     `gorm:"type:text;not null"`
+	Back           string     `gorm:"type:text;not null"`
+	EaseFactor     float64    `gorm:"not null;default:2.5"`
+	IntervalDays   int        `gorm:"not null;default:0"`
+	Repetitions    int        `gorm:"not null;default:0"`
+	DueDate        time.Time  `gorm:"type:date;not null;index:idx_flashcard_user_due,priority:2"`
+	LastReviewedAt *time.Time `gorm:"null"`
+	CreatedAt      time.Time
+}
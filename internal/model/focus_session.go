@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	FocusSessionRunning = "running"
+	FocusSessionPaused  = "paused"
+	FocusSessionEnded   = "ended"
+)
+
+type FocusSession struct {
+	ID             uint       `gorm:"primaryKey"`
+	UserID         uint       `gorm:"not null;index"`
+	TaskID         *uint      `gorm:"null;index"`
+	Status         string     `gorm:"size:20;not null;index"`
+	PlannedMinutes int        `gorm:"not null"`
+	StartedAt      time.Time  `gorm:"not null;index"`
+	PausedAt       *time.Time `gorm:"null"`
+	PausedSeconds  int        `gorm:"default:0"`
+	EndedAt        *time.Time `gorm:"null"`
+	ActualSeconds  int        `gorm:"default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	User User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Task *Task `gorm:"foreignKey:TaskID;constraint:OnDelete:SET NULL"`
+}
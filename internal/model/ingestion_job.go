@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	IngestionJobQueued    = "queued"
+	IngestionJobRunning   = "running"
+	IngestionJobSucceeded = "succeeded"
+	IngestionJobFailed    = "failed"
+)
+
+type IngestionJob struct {
+	ID         uint       `gorm:"primaryKey"`
+	UserID     uint       `gorm:"not null;index"`
+	SourceType string     `gorm:"size:50;not null"`
+	Title      string     `gorm:"size:255;not null"`
+	Source     string     `gorm:"size:255"`
+	FileData   []byte     `gorm:"type:bytea"`
+	Status     string     `gorm:"size:20;not null;index"`
+	Progress   int        `gorm:"default:0"`
+	Attempts   int        `gorm:"default:0"`
+	Error      string     `gorm:"type:text"`
+	MaterialID *uint      `gorm:"null"`
+	StartedAt  *time.Time `gorm:"null"`
+	FinishedAt *time.Time `gorm:"null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
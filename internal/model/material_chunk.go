@@ -0,0 +1,11 @@
+package model
+
+type MaterialChunk struct {
+	ID         uint   `gorm:"primaryKey"`
+	MaterialID uint   `gorm:"not null;uniqueIndex:idx_material_chunk,priority:1"`
+	ChunkIndex int    `gorm:"not null;uniqueIndex:idx_material_chunk,priority:2"`
+	Content    string `gorm:"type:text;not null"`
+	TokenCount int    `gorm:"not null"`
+
+	Material Material `gorm:"foreignKey:MaterialID;constraint:OnDelete:CASCADE"`
+}
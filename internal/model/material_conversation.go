@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+)
+
+// MaterialConversation adalah riwayat tanya jawab satu user dengan satu materi.
+type MaterialConversation struct {
+	ID         uint `gorm:"primaryKey"`
+	UserID     uint `gorm:"not null;uniqueIndex:idx_material_conversation,priority:1"`
+	MaterialID uint `gorm:"not null;uniqueIndex:idx_material_conversation,priority:2"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	Messages []MaterialChatMessage `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE"`
+	User     User                  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Material Material              `gorm:"foreignKey:MaterialID;constraint:OnDelete:CASCADE"`
+}
+
+type ChatCitation struct {
+	Number       int    `json:"number"`
+	PassageID    uint   `json:"passage_id"`
+	Page         *int   `json:"page,omitempty"`
+	StartSeconds *int   `json:"start_seconds,omitempty"`
+	Snippet      string `json:"snippet"`
+}
+
+type MaterialChatMessage struct {
+	ID             uint           `gorm:"primaryKey"`
+	ConversationID uint           `gorm:"not null;index"`
+	Role           string         `gorm:"size:20;not null"`
+	Content        string         `gorm:"type:text;not null"`
+	Citations      []ChatCitation `gorm:"type:jsonb;serializer:json"`
+	CreatedAt      time.Time
+}
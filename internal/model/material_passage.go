@@ -0,0 +1,16 @@
+package model
+
+// MaterialPassage adalah potongan kecil materi untuk retrieval chat. Berbeda
+// dengan MaterialChunk yang besar untuk rangkuman, passage menyimpan lokasi
+// asalnya (halaman PDF atau detik video) supaya jawaban bisa disitasi.
+type MaterialPassage struct {
+	ID           uint      `gorm:"primaryKey"`
+	MaterialID   uint      `gorm:"not null;uniqueIndex:idx_material_passage,priority:1"`
+	PassageIndex int       `gorm:"not null;uniqueIndex:idx_material_passage,priority:2"`
+	Content      string    `gorm:"type:text;not null"`
+	Page         *int      `gorm:"null"`
+	StartSeconds *int      `gorm:"null"`
+	Embedding    []float32 `gorm:"type:jsonb;serializer:json"`
+
+	Material Material `gorm:"foreignKey:MaterialID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+type MaterialSummary struct {
+	ID            uint   `gorm:"primaryKey"`
+	MaterialID    uint   `gorm:"not null;index:idx_material_summary_version;uniqueIndex:idx_material_summary_material_version,priority:1"`
+	PromptVersion string `gorm:"size:20;not null;index:idx_material_summary_version"`
+	Version       int    `gorm:"not null;uniqueIndex:idx_material_summary_material_version,priority:2"`
+	Summary       string `gorm:"type:text;not null"`
+	ChunkCount    int    `gorm:"default:1"`
+	CreatedAt     time.Time
+
+	Material Material `gorm:"foreignKey:MaterialID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	QuizTypeMultipleChoice = "multiple_choice"
+	QuizTypeTrueFalse      = "true_false"
+	QuizTypeMultipleAnswer = "multiple_answer"
+	QuizTypeShortAnswer    = "short_answer"
+	QuizTypeFillBlank      = "fill_blank"
+)
+
+const (
+	QuizDifficultyEasy   = "easy"
+	QuizDifficultyMedium = "medium"
+	QuizDifficultyHard   = "hard"
+)
+
+type Quiz struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	MaterialID uint   `gorm:"not null;index"`
+	Difficulty string `gorm:"size:20;not null;default:medium"`
+	CreatedAt  time.Time
+
+	Questions []QuizQuestion `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE"`
+	User      User           `gorm:"foreignKey:UserID"`
+	Material  Material       `gorm:"foreignKey:MaterialID;constraint:OnDelete:CASCADE"`
+}
+
+// QuizQuestion menyimpan jawaban sesuai tipenya: huruf untuk
+// multiple_choice ("B"), huruf terurut dipisah koma untuk multiple_answer
+// ("A,C"), "BENAR"/"SALAH" untuk true_false, dan teks jawaban untuk
+// short_answer serta fill_blank. JawabanAlternatif berisi jawaban teks lain
+// yang juga diterima.
+type QuizQuestion struct {
+	ID                uint                `gorm:"primaryKey"`
+	QuizID            uint                `gorm:"not null;index"`
+	Number            int                 `gorm:"not null"`
+	Type              string              `gorm:"size:30;not null;default:multiple_choice"`
+	Pertanyaan        string              `gorm:"type:text;not null"`
+	Pilihan           []map[string]string `gorm:"type:jsonb;serializer:json"`
+	JawabanBenar      string              `gorm:"size:255;not null"`
+	JawabanAlternatif []string            `gorm:"type:jsonb;serializer:json"`
+}
+
+type QuizAttemptAnswer struct {
+	QuestionID uint   `json:"question_id"`
+	Jawaban    string `json:"jawaban"`
+	IsCorrect  bool   `json:"is_correct"`
+}
+
+type QuizAttempt struct {
+	ID             uint                `gorm:"primaryKey"`
+	QuizID         uint                `gorm:"not null;index"`
+	UserID         uint                `gorm:"not null;index"`
+	MaterialID     uint                `gorm:"not null;index"`
+	TotalQuestions int                 `gorm:"not null"`
+	CorrectAnswers int                 `gorm:"default:0"`
+	Score          int                 `gorm:"default:0"`
+	Answers        []QuizAttemptAnswer `gorm:"type:jsonb;serializer:json"`
+	StartedAt      time.Time           `gorm:"not null"`
+	SubmittedAt    *time.Time          `gorm:"null"`
+
+	Quiz Quiz `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE"`
+}
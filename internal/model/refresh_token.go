@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+type RefreshToken struct {
+	ID           uint       `gorm:"primaryKey"`
+	UserID       uint       `gorm:"not null;index"`
+	TokenHash    string     `gorm:"size:64;not null;unique"`
+	ExpiresAt    time.Time  `gorm:"not null"`
+	RevokedAt    *time.Time `gorm:"null"`
+	ReplacedByID *uint      `gorm:"null"`
+	CreatedAt    time.Time
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
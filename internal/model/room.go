@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+type Room struct {
+	ID           uint   `gorm:"primaryKey"`
+	Name         string `gorm:"size:100;not null"`
+	OwnerID      uint   `gorm:"not null;index"`
+	FocusMinutes int    `gorm:"not null;default:25"`
+	BreakMinutes int    `gorm:"not null;default:5"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Owner User `gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"`
+}
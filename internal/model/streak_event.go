@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	StreakEventAwarded      = "awarded"
+	StreakEventReset        = "reset"
+	StreakEventFreezeUsed   = "freeze_used"
+	StreakEventFreezeEarned = "freeze_earned"
+)
+
+// StreakEvent adalah ledger perubahan streak user. Day adalah tanggal lokal
+// user yang menjadi dasar event (hari yang diselesaikan, atau hari yang
+// terlewat untuk reset dan freeze).
+type StreakEvent struct {
+	ID           uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"not null;index:idx_streak_event_user_created,priority:1"`
+	Type         string    `gorm:"size:20;not null"`
+	Day          time.Time `gorm:"type:date;not null"`
+	StreakBefore int       `gorm:"not null"`
+	StreakAfter  int       `gorm:"not null"`
+	FreezesAfter int       `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"index:idx_streak_event_user_created,priority:2"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
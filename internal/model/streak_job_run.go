@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	StreakJobRunning   = "running"
+	StreakJobSucceeded = "succeeded"
+	StreakJobFailed    = "failed"
+	StreakJobSkipped   = "skipped"
+
+	StreakJobTriggerSchedule = "schedule"
+	StreakJobTriggerManual   = "manual"
+)
+
+// StreakJobRun mencatat setiap eksekusi job evaluasi streak H-1, baik dari
+// scheduler maupun dipicu manual oleh admin.
+type StreakJobRun struct {
+	ID           uint       `gorm:"primaryKey"`
+	Trigger      string     `gorm:"size:20;not null"`
+	Status       string     `gorm:"size:20;not null;index"`
+	UsersScanned int        `gorm:"default:0"`
+	UsersChecked int        `gorm:"default:0"`
+	UsersReset   int        `gorm:"default:0"`
+	UsersFailed  int        `gorm:"default:0"`
+	Error        string     `gorm:"type:text"`
+	StartedAt    time.Time  `gorm:"not null;index"`
+	FinishedAt   *time.Time `gorm:"null"`
+	CreatedAt    time.Time
+}
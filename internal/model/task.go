@@ -5,13 +5,17 @@ import (
 )
 
 type Task struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Title       string    `gorm:"size:255;not null" json:"title"`
-	IsCompleted bool      `gorm:"default:false" json:"is_completed"`
-	TaskDate    time.Time `gorm:"type:date;not null" json:"task_date"`
-	UserID      uint      `gorm:"not null" json:"user_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Title        string    `gorm:"size:255;not null" json:"title"`
+	IsCompleted  bool      `gorm:"default:false" json:"is_completed"`
+	TaskDate     time.Time `gorm:"type:date;not null;uniqueIndex:idx_task_recurrence_date,priority:2" json:"task_date"`
+	UserID       uint      `gorm:"not null" json:"user_id"`
+	AssignedByID *uint     `gorm:"null;index" json:"assigned_by_id"`
+	RecurrenceID *uint     `gorm:"null;uniqueIndex:idx_task_recurrence_date,priority:1" json:"recurrence_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 
-	User User `gorm:"foreignKey:UserID"`
+	User       User            `gorm:"foreignKey:UserID"`
+	AssignedBy *User           `gorm:"foreignKey:AssignedByID"`
+	Recurrence *TaskRecurrence `gorm:"foreignKey:RecurrenceID;constraint:OnDelete:SET NULL"`
 }
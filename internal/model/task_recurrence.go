@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	RecurrenceDaily    = "daily"
+	RecurrenceWeekly   = "weekly"
+	RecurrenceInterval = "interval"
+)
+
+// TaskRecurrence adalah template task berulang. Task per tanggal dibuat
+// (materialize) dari template ini saat daftar task tanggal tersebut diminta,
+// sehingga status selesai tetap dicatat per hari.
+type TaskRecurrence struct {
+	ID            uint       `gorm:"primaryKey"`
+	UserID        uint       `gorm:"not null;index"`
+	Title         string     `gorm:"size:255;not null"`
+	Frequency     string     `gorm:"size:20;not null"`
+	Interval      int        `gorm:"not null;default:1"`
+	Weekdays      []int      `gorm:"type:jsonb;serializer:json"`
+	StartDate     time.Time  `gorm:"type:date;not null;index"`
+	UntilDate     *time.Time `gorm:"type:date;null"`
+	Count         *int       `gorm:"null"`
+	ExcludedDates []string   `gorm:"type:jsonb;serializer:json"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
@@ -11,10 +11,16 @@ type User struct {
 	PasswordHash          string     `gorm:"size:255;not null" json:"-"`
 	Role                  string     `gorm:"size:50;not null" json:"role"`
 	CurrentStreak         int        `gorm:"default:0" json:"current_streak"`
+	LongestStreak         int        `gorm:"default:0" json:"longest_streak"`
+	StreakFreezes         int        `gorm:"default:0" json:"streak_freezes"`
 	LastStreakUpdate      *time.Time `gorm:"null" json:"last_streak_update"`
 	LastStreakCheckDate   *time.Time `gorm:"null" json:"last_streak_check_date"`
 	LastStreakAwardedDate *time.Time `gorm:"null" json:"last_streak_awarded_date"`
 	KodePembimbing        *string    `gorm:"size:50;unique;null" json:"kode_pembimbing"`
+	PembimbingID          *uint      `gorm:"null;index" json:"pembimbing_id"`
+	TokenVersion          int        `gorm:"default:0" json:"-"`
+	TimeZone              string     `gorm:"size:64;not null;default:'Asia/Jakarta'" json:"time_zone"`
+	HideFromLeaderboard   bool       `gorm:"not null;default:false" json:"hide_from_leaderboard"`
 	CreatedAt             time.Time  `json:"created_at"`
 	UpdatedAt             time.Time  `json:"updated_at"`
 
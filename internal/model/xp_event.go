@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	XPSourceTaskCompleted = "task_completed"
+	XPSourceStreakDay     = "streak_day"
+	XPSourceQuizScore     = "quiz_score"
+)
+
+// XPEvent adalah ledger XP user. Ref mengidentifikasi sumbernya (misalnya
+// "task:12" atau "streak:2025-01-31") sehingga XP dari sumber yang sama tidak
+// pernah diberikan dua kali.
+type XPEvent struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_xp_event_user_ref,priority:1"`
+	Source    string    `gorm:"size:30;not null"`
+	Ref       string    `gorm:"size:64;not null;uniqueIndex:idx_xp_event_user_ref,priority:2"`
+	Amount    int       `gorm:"not null"`
+	CreatedAt time.Time `gorm:"index"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BadgeRepository struct {
+	db *gorm.DB
+}
+
+func NewBadgeRepository(db *gorm.DB) *BadgeRepository {
+	return &BadgeRepository{db: db}
+}
+
+func (r *BadgeRepository) Create(badge *model.Badge) (*model.Badge, error) {
+	err := r.db.Create(&badge).Error
+	return badge, err
+}
+
+func (r *BadgeRepository) FindByID(id uint) (*model.Badge, error) {
+	var badge model.Badge
+	err := r.db.Where("id = ?", id).First(&badge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+func (r *BadgeRepository) FindByCode(code string) (*model.Badge, error) {
+	var badge model.Badge
+	err := r.db.Where("code = ?", code).First(&badge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+func (r *BadgeRepository) FindAll() ([]model.Badge, error) {
+	var badges []model.Badge
+	err := r.db.Order("metric ASC, threshold ASC").Find(&badges).Error
+	return badges, err
+}
+
+func (r *BadgeRepository) FindActiveByMetrics(metrics []string) ([]model.Badge, error) {
+	var badges []model.Badge
+	err := r.db.Where("is_active = ? AND metric IN ?", true, metrics).Order("threshold ASC").Find(&badges).Error
+	return badges, err
+}
+
+func (r *BadgeRepository) Update(badge *model.Badge) (*model.Badge, error) {
+	err := r.db.Save(&badge).Error
+	return badge, err
+}
+
+func (r *BadgeRepository) Delete(id uint) error {
+	return r.db.Where("id = ?", id).Delete(&model.Badge{}).Error
+}
+
+// Award menyimpan badge untuk user. Hasilnya false jika badge sudah pernah
+// didapat, sehingga evaluasi yang berjalan bersamaan tetap aman.
+func (r *BadgeRepository) Award(userID, badgeID uint, earnedAt time.Time) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Omit("User", "Badge").Create(&model.UserBadge{
+		UserID:   userID,
+		BadgeID:  badgeID,
+		EarnedAt: earnedAt,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *BadgeRepository) FindUserBadges(userID uint) ([]model.UserBadge, error) {
+	var userBadges []model.UserBadge
+	err := r.db.Where("user_id = ?", userID).Order("earned_at DESC").Find(&userBadges).Error
+	return userBadges, err
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+)
+
+type FlashcardRepository struct {
+	db *gorm.DB
+}
+
+func NewFlashcardRepository(db *gorm.DB) *FlashcardRepository {
+	return &FlashcardRepository{db: db}
+}
+
+func (r *FlashcardRepository) CreateDeck(deck *model.FlashcardDeck) (*model.FlashcardDeck, error) {
+	err := r.db.Omit("User", "Material").Create(&deck).Error
+	return deck, err
+}
+
+func (r *FlashcardRepository) FindByID(id, userID uint) (*model.Flashcard, error) {
+	var card model.Flashcard
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&card).Error
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// FindDue mengambil kartu yang jatuh tempo sampai tanggal today, paling lama
+// tertunda lebih dulu.
+func (r *FlashcardRepository) FindDue(userID uint, today time.Time, limit int) ([]model.Flashcard, int64, error) {
+	var cards []model.Flashcard
+	var total int64
+
+	query := r.db.Model(&model.Flashcard{}).Where("user_id = ? AND due_date <= ?", userID, today)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("due_date ASC, id ASC").Limit(limit).Find(&cards).Error
+	return cards, total, err
+}
+
+func (r *FlashcardRepository) Update(card *model.Flashcard) (*model.Flashcard, error) {
+	err := r.db.Save(&card).Error
+	return card, err
+}
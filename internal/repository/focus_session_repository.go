@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+)
+
+type FocusSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewFocusSessionRepository(db *gorm.DB) *FocusSessionRepository {
+	return &FocusSessionRepository{db: db}
+}
+
+func (r *FocusSessionRepository) Create(session *model.FocusSession) (*model.FocusSession, error) {
+	err := r.db.Create(&session).Error
+	return session, err
+}
+
+func (r *FocusSessionRepository) FindByID(id, userID uint) (*model.FocusSession, error) {
+	var session model.FocusSession
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *FocusSessionRepository) FindActiveByUserID(userID uint) (*model.FocusSession, error) {
+	var session model.FocusSession
+	err := r.db.Where("user_id = ? AND status IN ?", userID, []string{model.FocusSessionRunning, model.FocusSessionPaused}).
+		Order("started_at DESC").First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *FocusSessionRepository) Update(session *model.FocusSession) (*model.FocusSession, error) {
+	err := r.db.Omit("User", "Task").Save(&session).Error
+	return session, err
+}
+
+// FindByUserIDBetween mengambil sesi yang dimulai di rentang [start, end).
+func (r *FocusSessionRepository) FindByUserIDBetween(userID uint, start, end time.Time) ([]model.FocusSession, error) {
+	var sessions []model.FocusSession
+	err := r.db.Where("user_id = ? AND started_at >= ? AND started_at < ?", userID, start, end).
+		Order("started_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *FocusSessionRepository) SumEndedSeconds(userID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&model.FocusSession{}).
+		Select("COALESCE(SUM(actual_seconds), 0)").
+		Where("user_id = ? AND status = ?", userID, model.FocusSessionEnded).
+		Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IngestionJobRepository struct {
+	db *gorm.DB
+}
+
+func NewIngestionJobRepository(db *gorm.DB) *IngestionJobRepository {
+	return &IngestionJobRepository{db: db}
+}
+
+func (r *IngestionJobRepository) Create(job *model.IngestionJob) (*model.IngestionJob, error) {
+	err := r.db.Create(&job).Error
+	return job, err
+}
+
+func (r *IngestionJobRepository) FindByID(id, userID uint) (*model.IngestionJob, error) {
+	var job model.IngestionJob
+	err := r.db.Omit("file_data").Where("id = ? AND user_id = ?", id, userID).First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ClaimNext mengambil satu job yang masih antre dan menandainya running.
+// SKIP LOCKED membuat beberapa worker (atau replika) tidak mengambil job yang sama.
+func (r *IngestionJobRepository) ClaimNext() (*model.IngestionJob, error) {
+	var job model.IngestionJob
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", model.IngestionJobQueued).
+			Order("id ASC").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		job.Status = model.IngestionJobRunning
+		job.StartedAt = &now
+		job.Attempts++
+		return tx.Save(&job).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *IngestionJobRepository) UpdateProgress(id uint, progress int) error {
+	return r.db.Model(&model.IngestionJob{}).Where("id = ?", id).Update("progress", progress).Error
+}
+
+// Touch memperbarui updated_at job yang masih dikerjakan supaya tidak
+// dianggap macet oleh RequeueStale. Hasil false berarti job sudah tidak
+// dipegang percobaan ini lagi.
+func (r *IngestionJobRepository) Touch(id uint, attempts int) (bool, error) {
+	result := r.db.Model(&model.IngestionJob{}).
+		Where("id = ? AND status = ? AND attempts = ?", id, model.IngestionJobRunning, attempts).
+		Update("updated_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// MarkSucceeded dan MarkFailed hanya berlaku untuk percobaan yang masih
+// running, supaya worker yang sudah dianggap macet tidak menimpa hasil
+// percobaan berikutnya.
+func (r *IngestionJobRepository) MarkSucceeded(id uint, attempts int, materialID uint) (bool, error) {
+	result := r.db.Model(&model.IngestionJob{}).
+		Where("id = ? AND status = ? AND attempts = ?", id, model.IngestionJobRunning, attempts).
+		Updates(map[string]interface{}{
+			"status":      model.IngestionJobSucceeded,
+			"progress":    100,
+			"material_id": materialID,
+			"file_data":   nil,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *IngestionJobRepository) MarkFailed(id uint, attempts int, message string) (bool, error) {
+	result := r.db.Model(&model.IngestionJob{}).
+		Where("id = ? AND status = ? AND attempts = ?", id, model.IngestionJobRunning, attempts).
+		Updates(map[string]interface{}{
+			"status":      model.IngestionJobFailed,
+			"error":       message,
+			"file_data":   nil,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// RequeueStale mengembalikan job running yang tidak bergerak sejak staleBefore
+// ke antrean, misalnya karena proses API mati di tengah jalan. Job yang sudah
+// mencapai maxAttempts ditandai gagal.
+func (r *IngestionJobRepository) RequeueStale(staleBefore time.Time, maxAttempts int) (int64, error) {
+	err := r.db.Model(&model.IngestionJob{}).
+		Where("status = ? AND updated_at < ? AND attempts >= ?", model.IngestionJobRunning, staleBefore, maxAttempts).
+		Updates(map[string]interface{}{
+			"status":      model.IngestionJobFailed,
+			"error":       "job terhenti terlalu sering dan dibatalkan",
+			"file_data":   nil,
+			"finished_at": time.Now(),
+		}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	result := r.db.Model(&model.IngestionJob{}).
+		Where("status = ? AND updated_at < ?", model.IngestionJobRunning, staleBefore).
+		Updates(map[string]interface{}{
+			"status":   model.IngestionJobQueued,
+			"progress": 0,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MaterialChatRepository struct {
+	db *gorm.DB
+}
+
+func NewMaterialChatRepository(db *gorm.DB) *MaterialChatRepository {
+	return &MaterialChatRepository{db: db}
+}
+
+// FindOrCreateConversation mengambil percakapan user untuk materi tersebut,
+// atau membuatnya jika belum ada.
+func (r *MaterialChatRepository) FindOrCreateConversation(userID, materialID uint) (*model.MaterialConversation, error) {
+	conversation := model.MaterialConversation{UserID: userID, MaterialID: materialID}
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Omit("User", "Material").Create(&conversation).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Where("user_id = ? AND material_id = ?", userID, materialID).First(&conversation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (r *MaterialChatRepository) FindConversation(userID, materialID uint) (*model.MaterialConversation, error) {
+	var conversation model.MaterialConversation
+	err := r.db.Where("user_id = ? AND material_id = ?", userID, materialID).First(&conversation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// FindRecentMessages mengambil limit pesan terakhir, diurutkan dari yang
+// paling lama.
+func (r *MaterialChatRepository) FindRecentMessages(conversationID uint, limit int) ([]model.MaterialChatMessage, error) {
+	var messages []model.MaterialChatMessage
+	err := r.db.Where("conversation_id = ?", conversationID).Order("id DESC").Limit(limit).Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+func (r *MaterialChatRepository) CreateMessages(messages []model.MaterialChatMessage) error {
+	return r.db.Create(&messages).Error
+}
+
+func (r *MaterialChatRepository) DeleteConversation(userID, materialID uint) error {
+	return r.db.Where("user_id = ? AND material_id = ?", userID, materialID).Delete(&model.MaterialConversation{}).Error
+}
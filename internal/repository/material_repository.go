@@ -3,6 +3,7 @@ package repository
 import (
 	"github.com/mohamadarif03/focus-room-be/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MaterialRepository struct {
@@ -18,8 +19,107 @@ func (r *MaterialRepository) Save(material *model.Material) (*model.Material, er
 	return material, err
 }
 
+// SaveWithContent menyimpan materi beserta chunk dan passage-nya dalam satu
+// transaksi, supaya tidak ada materi yang tersimpan tanpa isi. MaterialID pada
+// chunks dan passages diisi dari ID materi yang baru dibuat.
+func (r *MaterialRepository) SaveWithContent(material *model.Material, chunks []model.MaterialChunk, passages []model.MaterialPassage) (*model.Material, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(material).Error; err != nil {
+			return err
+		}
+		for i := range chunks {
+			chunks[i].MaterialID = material.ID
+		}
+		for i := range passages {
+			passages[i].MaterialID = material.ID
+		}
+		if len(chunks) > 0 {
+			if err := tx.Create(&chunks).Error; err != nil {
+				return err
+			}
+		}
+		if len(passages) > 0 {
+			if err := tx.Omit("Material").Create(&passages).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return material, err
+}
+
 func (r *MaterialRepository) FindByID(id, userID uint) (*model.Material, error) {
 	var material model.Material
 	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&material).Error
 	return &material, err
-}
\ No newline at end of file
+}
+
+func (r *MaterialRepository) FindAllByUserID(userID uint, sourceType, search string, limit, offset int) ([]model.Material, int64, error) {
+	var materials []model.Material
+	var total int64
+
+	query := r.db.Model(&model.Material{}).Where("user_id = ?", userID)
+	if sourceType != "" {
+		query = query.Where("source_type = ?", sourceType)
+	}
+	if search != "" {
+		query = query.Where("title ILIKE ?", "%"+search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Omit("extracted_text").Order("created_at DESC").Limit(limit).Offset(offset).Find(&materials).Error
+	return materials, total, err
+}
+
+func (r *MaterialRepository) Update(material *model.Material) (*model.Material, error) {
+	err := r.db.Save(&material).Error
+	return material, err
+}
+
+func (r *MaterialRepository) Delete(id, userID uint) error {
+	return r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Material{}).Error
+}
+
+// SaveChunks melewati chunk yang indeksnya sudah tersimpan, sama seperti
+// SavePassages.
+func (r *MaterialRepository) SaveChunks(chunks []model.MaterialChunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	return r.db.Omit("Material").Clauses(clause.OnConflict{DoNothing: true}).Create(&chunks).Error
+}
+
+func (r *MaterialRepository) FindChunksByMaterialID(materialID uint) ([]model.MaterialChunk, error) {
+	var chunks []model.MaterialChunk
+	err := r.db.Where("material_id = ?", materialID).Order("chunk_index ASC").Find(&chunks).Error
+	return chunks, err
+}
+
+// SavePassages melewati passage yang indeksnya sudah tersimpan, sehingga dua
+// request yang menyiapkan materi yang sama tidak membuat passage ganda.
+func (r *MaterialRepository) SavePassages(passages []model.MaterialPassage) error {
+	if len(passages) == 0 {
+		return nil
+	}
+	return r.db.Omit("Material").Clauses(clause.OnConflict{DoNothing: true}).Create(&passages).Error
+}
+
+func (r *MaterialRepository) FindPassagesByMaterialID(materialID uint) ([]model.MaterialPassage, error) {
+	var passages []model.MaterialPassage
+	err := r.db.Where("material_id = ?", materialID).Order("passage_index ASC").Find(&passages).Error
+	return passages, err
+}
+
+func (r *MaterialRepository) UpdatePassageEmbeddings(passages []model.MaterialPassage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range passages {
+			if err := tx.Model(&passages[i]).Select("Embedding").Updates(&passages[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
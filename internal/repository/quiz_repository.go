@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+)
+
+type QuizRepository struct {
+	db *gorm.DB
+}
+
+func NewQuizRepository(db *gorm.DB) *QuizRepository {
+	return &QuizRepository{db: db}
+}
+
+func (r *QuizRepository) Save(quiz *model.Quiz) (*model.Quiz, error) {
+	err := r.db.Create(&quiz).Error
+	return quiz, err
+}
+
+func (r *QuizRepository) FindByID(id, userID uint) (*model.Quiz, error) {
+	var quiz model.Quiz
+	err := r.db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("number ASC")
+	}).Where("id = ? AND user_id = ?", id, userID).First(&quiz).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+func (r *QuizRepository) CreateAttempt(attempt *model.QuizAttempt) (*model.QuizAttempt, error) {
+	err := r.db.Create(&attempt).Error
+	return attempt, err
+}
+
+func (r *QuizRepository) FindAttemptByID(id, userID uint) (*model.QuizAttempt, error) {
+	var attempt model.QuizAttempt
+	err := r.db.Preload("Quiz.Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("number ASC")
+	}).Where("id = ? AND user_id = ?", id, userID).First(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// SubmitAttempt menyimpan hasil attempt hanya jika attempt itu belum
+// dikumpulkan. Hasil false berarti request lain sudah mengumpulkannya lebih
+// dulu.
+func (r *QuizRepository) SubmitAttempt(attempt *model.QuizAttempt) (bool, error) {
+	result := r.db.Model(&model.QuizAttempt{}).
+		Where("id = ? AND submitted_at IS NULL", attempt.ID).
+		Select("Answers", "CorrectAnswers", "TotalQuestions", "Score", "SubmittedAt").
+		Updates(attempt)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *QuizRepository) FindAttemptsByMaterialID(userID, materialID uint) ([]model.QuizAttempt, error) {
+	var attempts []model.QuizAttempt
+	err := r.db.Where("user_id = ? AND material_id = ?", userID, materialID).Order("started_at DESC").Find(&attempts).Error
+	return attempts, err
+}
+
+// CountSubmittedQuizzes menghitung kuis yang sudah dikumpulkan user. Hanya
+// attempt pertama tiap kuis yang dinilai, jadi mengulang kuis yang sama tidak
+// menambah hitungan. Jika minScore > 0, hanya kuis yang attempt pertamanya
+// mencapai skor itu yang dihitung.
+func (r *QuizRepository) CountSubmittedQuizzes(userID uint, minScore int) (int64, error) {
+	firstAttempts := r.db.Model(&model.QuizAttempt{}).
+		Select("DISTINCT ON (quiz_id) quiz_id, score").
+		Where("user_id = ? AND submitted_at IS NOT NULL", userID).
+		Order("quiz_id, submitted_at ASC, id ASC")
+
+	var count int64
+	query := r.db.Table("(?) AS first_attempts", firstAttempts)
+	if minScore > 0 {
+		query = query.Where("score >= ?", minScore)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
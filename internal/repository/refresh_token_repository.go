@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(token *model.RefreshToken) (*model.RefreshToken, error) {
+	err := r.db.Create(&token).Error
+	return token, err
+}
+
+func (r *RefreshTokenRepository) FindByHash(tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Revoke menandai token dicabut hanya jika belum pernah dicabut. Nilai false
+// berarti token sudah dipakai sebelumnya, misalnya oleh request lain yang
+// berjalan bersamaan. Saat rotasi, token pengganti dicatat terpisah lewat
+// SetReplacedBy karena token baru baru dibuat setelah token lama dicabut.
+func (r *RefreshTokenRepository) Revoke(id uint) (bool, error) {
+	result := r.db.Model(&model.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *RefreshTokenRepository) SetReplacedBy(id uint, replacedByID uint) error {
+	return r.db.Model(&model.RefreshToken{}).Where("id = ?", id).Update("replaced_by_id", replacedByID).Error
+}
+
+func (r *RefreshTokenRepository) RevokeAllByUserID(userID uint) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
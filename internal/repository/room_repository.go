@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+)
+
+type RoomRepository struct {
+	db *gorm.DB
+}
+
+func NewRoomRepository(db *gorm.DB) *RoomRepository {
+	return &RoomRepository{db: db}
+}
+
+func (r *RoomRepository) Create(room *model.Room) (*model.Room, error) {
+	err := r.db.Create(&room).Error
+	return room, err
+}
+
+func (r *RoomRepository) FindByID(id uint) (*model.Room, error) {
+	var room model.Room
+	err := r.db.Preload("Owner").First(&room, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (r *RoomRepository) FindAll() ([]model.Room, error) {
+	var rooms []model.Room
+	err := r.db.Preload("Owner").Order("created_at DESC").Find(&rooms).Error
+	return rooms, err
+}
+
+func (r *RoomRepository) Delete(id, ownerID uint) (bool, error) {
+	result := r.db.Where("id = ? AND owner_id = ?", id, ownerID).Delete(&model.Room{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+)
+
+type StreakEventRepository struct {
+	db *gorm.DB
+}
+
+func NewStreakEventRepository(db *gorm.DB) *StreakEventRepository {
+	return &StreakEventRepository{db: db}
+}
+
+func (r *StreakEventRepository) FindByUserID(userID uint, limit, offset int) ([]model.StreakEvent, int64, error) {
+	var events []model.StreakEvent
+	var total int64
+
+	query := r.db.Model(&model.StreakEvent{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&events).Error
+	return events, total, err
+}
+
+// FindDaysByTypes mengembalikan tanggal unik di rentang [from, to] yang
+// memiliki event dengan salah satu tipe types.
+func (r *StreakEventRepository) FindDaysByTypes(userID uint, from, to time.Time, types []string) ([]time.Time, error) {
+	var days []time.Time
+	err := r.db.Model(&model.StreakEvent{}).Distinct("day").
+		Where("user_id = ? AND day >= ? AND day <= ? AND type IN ?", userID, from, to, types).
+		Order("day ASC").Pluck("day", &days).Error
+	return days, err
+}
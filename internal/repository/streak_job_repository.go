@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+)
+
+type StreakJobRepository struct {
+	db *gorm.DB
+}
+
+func NewStreakJobRepository(db *gorm.DB) *StreakJobRepository {
+	return &StreakJobRepository{db: db}
+}
+
+// WithAdvisoryLock menjalankan fn hanya jika advisory lock Postgres dengan key
+// tersebut berhasil diambil. Lock dipegang di satu koneksi yang sama sampai fn
+// selesai, sehingga replika lain yang mencoba di waktu bersamaan akan dilewati.
+func (r *StreakJobRepository) WithAdvisoryLock(key int64, fn func() error) (bool, error) {
+	acquired := false
+	err := r.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", key).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if !acquired {
+			return nil
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", key)
+
+		return fn()
+	})
+	return acquired, err
+}
+
+func (r *StreakJobRepository) CreateRun(run *model.StreakJobRun) (*model.StreakJobRun, error) {
+	err := r.db.Create(&run).Error
+	return run, err
+}
+
+func (r *StreakJobRepository) UpdateRun(run *model.StreakJobRun) error {
+	return r.db.Save(run).Error
+}
+
+func (r *StreakJobRepository) FindLatestRuns(limit int) ([]model.StreakJobRun, error) {
+	var runs []model.StreakJobRun
+	err := r.db.Order("started_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SummaryRepository struct {
+	db *gorm.DB
+}
+
+func NewSummaryRepository(db *gorm.DB) *SummaryRepository {
+	return &SummaryRepository{db: db}
+}
+
+// SaveNextVersion mengisi summary.Version dengan versi berikutnya untuk
+// materinya lalu menyimpannya. Baris materi dikunci FOR NO KEY UPDATE selama
+// transaksi, sehingga dua regenerate bersamaan mendapat versi berurutan dan
+// bukan dua versi yang sama.
+func (r *SummaryRepository) SaveNextVersion(summary *model.MaterialSummary) (*model.MaterialSummary, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var material model.Material
+		err := tx.Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+			Select("id").Where("id = ?", summary.MaterialID).
+			First(&material).Error
+		if err != nil {
+			return err
+		}
+
+		var latest int
+		err = tx.Model(&model.MaterialSummary{}).Where("material_id = ?", summary.MaterialID).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		summary.Version = latest + 1
+		return tx.Omit("Material").Create(summary).Error
+	})
+	return summary, err
+}
+
+func (r *SummaryRepository) FindLatest(materialID uint, promptVersion string) (*model.MaterialSummary, error) {
+	var summary model.MaterialSummary
+	err := r.db.Where("material_id = ? AND prompt_version = ?", materialID, promptVersion).Order("version DESC").First(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *SummaryRepository) FindByMaterialID(materialID uint) ([]model.MaterialSummary, error) {
+	var summaries []model.MaterialSummary
+	err := r.db.Where("material_id = ?", materialID).Order("version DESC").Find(&summaries).Error
+	return summaries, err
+}
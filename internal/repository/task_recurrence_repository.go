@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+)
+
+type TaskRecurrenceRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskRecurrenceRepository(db *gorm.DB) *TaskRecurrenceRepository {
+	return &TaskRecurrenceRepository{db: db}
+}
+
+func (r *TaskRecurrenceRepository) Create(recurrence *model.TaskRecurrence) (*model.TaskRecurrence, error) {
+	err := r.db.Create(&recurrence).Error
+	return recurrence, err
+}
+
+func (r *TaskRecurrenceRepository) FindByID(id, userID uint) (*model.TaskRecurrence, error) {
+	var recurrence model.TaskRecurrence
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&recurrence).Error
+	if err != nil {
+		return nil, err
+	}
+	return &recurrence, nil
+}
+
+func (r *TaskRecurrenceRepository) FindByUserID(userID uint) ([]model.TaskRecurrence, error) {
+	var recurrences []model.TaskRecurrence
+	err := r.db.Where("user_id = ?", userID).Order("start_date ASC, id ASC").Find(&recurrences).Error
+	return recurrences, err
+}
+
+// FindActiveBetween mengambil template yang rentang berlakunya beririsan
+// dengan [start, end]. Apakah template benar-benar jatuh di suatu tanggal
+// tetap ditentukan di service.
+func (r *TaskRecurrenceRepository) FindActiveBetween(userID uint, start, end time.Time) ([]model.TaskRecurrence, error) {
+	var recurrences []model.TaskRecurrence
+	err := r.db.Where("user_id = ? AND start_date <= ? AND (until_date IS NULL OR until_date >= ?)", userID, end, start).
+		Order("id ASC").Find(&recurrences).Error
+	return recurrences, err
+}
+
+func (r *TaskRecurrenceRepository) Update(recurrence *model.TaskRecurrence) (*model.TaskRecurrence, error) {
+	err := r.db.Omit("User").Save(&recurrence).Error
+	return recurrence, err
+}
+
+func (r *TaskRecurrenceRepository) Delete(id uint) error {
+	return r.db.Delete(&model.TaskRecurrence{}, id).Error
+}
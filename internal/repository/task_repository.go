@@ -5,6 +5,7 @@ import (
 
 	"github.com/mohamadarif03/focus-room-be/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskRepository struct {
@@ -53,3 +54,73 @@ func (r *TaskRepository) Delete(id uint) error {
 	err := r.db.Unscoped().Where("id = ?", id).Delete(&model.Task{}).Error
 	return err
 }
+
+func (r *TaskRepository) FindTasksByAssignerIDAndDate(assignerID uint, date time.Time) ([]model.Task, error) {
+	var tasks []model.Task
+
+	err := r.db.Preload("User").Where("assigned_by_id = ? AND task_date = ?", assignerID, date).Order("user_id ASC, created_at DESC").Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// CreateTasks menyimpan semua task dalam satu INSERT, jadi gagal sebagian
+// tidak meninggalkan task yang hanya terbuat untuk sebagian user.
+func (r *TaskRepository) CreateTasks(tasks []model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return r.db.Create(&tasks).Error
+}
+
+// CreateOccurrences menyimpan task hasil materialize template berulang.
+// Occurrence yang sudah ada (recurrence_id + task_date sama) dilewati sehingga
+// aman dipanggil bersamaan.
+func (r *TaskRepository) CreateOccurrences(tasks []model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tasks).Error
+}
+
+func (r *TaskRepository) UpdateRecurrenceFrom(recurrenceID uint, from time.Time, newRecurrenceID uint, title string) error {
+	return r.db.Model(&model.Task{}).
+		Where("recurrence_id = ? AND task_date >= ?", recurrenceID, from).
+		Updates(map[string]interface{}{"recurrence_id": newRecurrenceID, "title": title}).Error
+}
+
+func (r *TaskRepository) DeleteByRecurrenceFrom(recurrenceID uint, from time.Time) error {
+	return r.db.Unscoped().Where("recurrence_id = ? AND task_date >= ?", recurrenceID, from).Delete(&model.Task{}).Error
+}
+
+// TaskDayCount adalah ringkasan jumlah task per tanggal.
+type TaskDayCount struct {
+	TaskDate  time.Time
+	Total     int
+	Completed int
+}
+
+func (r *TaskRepository) FindTasksByUserIDBetween(userID uint, from, to time.Time) ([]model.Task, error) {
+	var tasks []model.Task
+
+	err := r.db.Where("user_id = ? AND task_date >= ? AND task_date <= ?", userID, from, to).
+		Order("task_date ASC, created_at DESC").Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *TaskRepository) CountTasksByUserIDBetween(userID uint, from, to time.Time) ([]TaskDayCount, error) {
+	var counts []TaskDayCount
+
+	err := r.db.Model(&model.Task{}).
+		Select("task_date, COUNT(*) AS total, SUM(CASE WHEN is_completed THEN 1 ELSE 0 END) AS completed").
+		Where("user_id = ? AND task_date >= ? AND task_date <= ?", userID, from, to).
+		Group("task_date").Order("task_date ASC").Scan(&counts).Error
+	return counts, err
+}
+
+func (r *TaskRepository) CountCompletedByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Task{}).Where("user_id = ? AND is_completed = ?", userID, true).Count(&count).Error
+	return count, err
+}
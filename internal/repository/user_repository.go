@@ -4,8 +4,19 @@ import (
 	"github.com/mohamadarif03/focus-room-be/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// streakColumns adalah kolom yang ditulis UpdateStreak. Kolom lain tidak ikut
+// disimpan supaya perubahan profil yang terjadi bersamaan tidak tertimpa.
+var streakColumns = []string{
+	"current_streak",
+	"longest_streak",
+	"streak_freezes",
+	"last_streak_check_date",
+	"last_streak_awarded_date",
+}
+
 type UserRepository struct {
 	DB *gorm.DB
 }
@@ -38,9 +49,12 @@ func (r *UserRepository) FindByID(id uint) (*model.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) Update(user *model.User) (*model.User, error) {
-	err := r.DB.Save(&user).Error
-	return user, err
+// UpdateColumns hanya menulis kolom yang diberikan. Jangan menyimpan seluruh
+// baris user: kolom streak ditulis UpdateStreak di bawah lock dan
+// token_version dinaikkan atomik oleh IncrementTokenVersion, jadi salinan
+// user yang sudah basi bisa menimpa keduanya.
+func (r *UserRepository) UpdateColumns(id uint, columns map[string]interface{}) error {
+	return r.DB.Model(&model.User{}).Where("id = ?", id).Updates(columns).Error
 }
 
 func (r *UserRepository) Delete(id uint) error {
@@ -55,3 +69,66 @@ func (r *UserRepository) CreateUser(user *model.User) (*model.User, error) {
 	}
 	return user, nil
 }
+
+func (r *UserRepository) FindByKodePembimbing(kode string) (*model.User, error) {
+	var user model.User
+	err := r.DB.Where("kode_pembimbing = ? AND role = ?", kode, "pembimbing").First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) FindStudentsByPembimbingID(pembimbingID uint) ([]model.User, error) {
+	var users []model.User
+	err := r.DB.Where("pembimbing_id = ? AND role = ?", pembimbingID, "siswa").Order("username ASC").Find(&users).Error
+	return users, err
+}
+
+func (r *UserRepository) IncrementTokenVersion(id uint) error {
+	return r.DB.Model(&model.User{}).Where("id = ?", id).Update("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+// FindStudentsAfterID mengambil siswa secara bertahap (keyset pagination)
+// untuk job yang memproses semua siswa.
+func (r *UserRepository) FindStudentsAfterID(afterID uint, limit int) ([]model.User, error) {
+	var users []model.User
+	err := r.DB.Where("role = ? AND id > ?", "siswa", afterID).Order("id ASC").Limit(limit).Find(&users).Error
+	return users, err
+}
+
+// UpdateStreak mengunci baris user lalu memanggil apply dengan data terbaru.
+// Jika apply mengembalikan changed, kolom streak dan event ledger disimpan di
+// transaksi yang sama, sehingga dua request bersamaan tidak bisa menaikkan
+// atau me-reset streak dua kali.
+//
+// Dipakai FOR NO KEY UPDATE (bukan FOR UPDATE) agar insert task yang hanya
+// butuh KEY SHARE lewat foreign key tidak ikut terblokir.
+func (r *UserRepository) UpdateStreak(userID uint, apply func(user *model.User) (changed bool, events []model.StreakEvent)) (*model.User, error) {
+	var user model.User
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+			Where("id = ?", userID).
+			First(&user).Error
+		if err != nil {
+			return err
+		}
+
+		changed, events := apply(&user)
+		if !changed {
+			return nil
+		}
+
+		if err := tx.Model(&user).Select(streakColumns).Updates(&user).Error; err != nil {
+			return err
+		}
+		if len(events) > 0 {
+			return tx.Omit("User").Create(&events).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LeaderboardFilter membatasi user yang ikut dalam leaderboard. Since nil
+// berarti all-time; PembimbingID dan UserIDs nil berarti tanpa batasan.
+type LeaderboardFilter struct {
+	Since        *time.Time
+	PembimbingID *uint
+	UserIDs      []uint
+}
+
+type LeaderboardRow struct {
+	UserID   uint
+	Username string
+	XP       int
+}
+
+type XPRepository struct {
+	db *gorm.DB
+}
+
+func NewXPRepository(db *gorm.DB) *XPRepository {
+	return &XPRepository{db: db}
+}
+
+// Grant menambah XP ke ledger. Hasilnya false jika Ref yang sama sudah pernah
+// dicatat untuk user tersebut.
+func (r *XPRepository) Grant(event *model.XPEvent) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Omit("User").Create(event)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *XPRepository) SumByUserID(userID uint, since *time.Time) (int, error) {
+	var total int
+	query := r.db.Model(&model.XPEvent{}).Select("COALESCE(SUM(amount), 0)").Where("user_id = ?", userID)
+	if since != nil {
+		query = query.Where("created_at >= ?", *since)
+	}
+	err := query.Scan(&total).Error
+	return total, err
+}
+
+// Leaderboard mengurutkan siswa yang tidak opt-out berdasarkan total XP.
+func (r *XPRepository) Leaderboard(filter LeaderboardFilter, limit int) ([]LeaderboardRow, error) {
+	var rows []LeaderboardRow
+	err := r.leaderboardQuery(filter).
+		Select("users.id AS user_id, users.username, SUM(xp_events.amount) AS xp").
+		Group("users.id, users.username").
+		Order("xp DESC, users.id ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// CountAhead menghitung siswa di leaderboard yang XP-nya lebih besar dari xp,
+// dipakai untuk menentukan peringkat user yang tidak masuk halaman teratas.
+func (r *XPRepository) CountAhead(filter LeaderboardFilter, xp int) (int64, error) {
+	var count int64
+	subQuery := r.leaderboardQuery(filter).
+		Select("users.id").
+		Group("users.id").
+		Having("SUM(xp_events.amount) > ?", xp)
+	err := r.db.Table("(?) AS ahead", subQuery).Count(&count).Error
+	return count, err
+}
+
+func (r *XPRepository) leaderboardQuery(filter LeaderboardFilter) *gorm.DB {
+	query := r.db.Model(&model.XPEvent{}).
+		Joins("JOIN users ON users.id = xp_events.user_id").
+		Where("users.role = ? AND users.hide_from_leaderboard = ?", "siswa", false)
+
+	if filter.Since != nil {
+		query = query.Where("xp_events.created_at >= ?", *filter.Since)
+	}
+	if filter.PembimbingID != nil {
+		query = query.Where("users.pembimbing_id = ?", *filter.PembimbingID)
+	}
+	if filter.UserIDs != nil {
+		query = query.Where("users.id IN ?", filter.UserIDs)
+	}
+	return query
+}
@@ -13,6 +13,17 @@ func SetupRouter(
 	authService *service.AuthService,
 	taskService *service.TaskService,
 	aiService *service.AIService,
+	mentorService *service.MentorService,
+	quizService *service.QuizService,
+	materialService *service.MaterialService,
+	ingestionService *service.IngestionService,
+	focusService *service.FocusService,
+	roomService *service.RoomService,
+	streakScheduler *service.StreakScheduler,
+	achievementService *service.AchievementService,
+	xpService *service.XPService,
+	flashcardService *service.FlashcardService,
+	materialChatService *service.MaterialChatService,
 ) *gin.Engine {
 
 	r := gin.Default()
@@ -21,6 +32,17 @@ func SetupRouter(
 	authHandler := handler.NewAuthHandler(authService)
 	taskHandler := handler.NewTaskHandler(taskService)
 	aiHandler := handler.NewAIHandler(aiService)
+	mentorHandler := handler.NewMentorHandler(mentorService)
+	quizHandler := handler.NewQuizHandler(quizService)
+	materialHandler := handler.NewMaterialHandler(materialService)
+	ingestionHandler := handler.NewIngestionHandler(ingestionService)
+	focusHandler := handler.NewFocusHandler(focusService)
+	roomHandler := handler.NewRoomHandler(roomService)
+	streakHandler := handler.NewStreakHandler(streakScheduler)
+	achievementHandler := handler.NewAchievementHandler(achievementService)
+	xpHandler := handler.NewXPHandler(xpService)
+	flashcardHandler := handler.NewFlashcardHandler(flashcardService)
+	materialChatHandler := handler.NewMaterialChatHandler(materialChatService)
 
 	api := r.Group("/api/v1")
 	{
@@ -28,52 +50,139 @@ func SetupRouter(
 		{
 			authGroup.POST("/register", authHandler.Register)
 			authGroup.POST("/login", authHandler.Login)
+			authGroup.POST("/refresh", authHandler.Refresh)
+			authGroup.POST("/logout", authHandler.Logout)
 		}
 
 		authedGroup := api.Group("/")
-		authedGroup.Use(middleware.AuthMiddleware())
+		authedGroup.Use(middleware.AuthMiddleware(authService))
 		{
 			authedGroup.GET("/users/me", userHandler.GetSelf)
+			authedGroup.PUT("/users/me/timezone", userHandler.UpdateTimeZone)
+			authedGroup.GET("/users/me/achievements", achievementHandler.GetMyAchievements)
+			authedGroup.GET("/users/me/xp", xpHandler.GetMyXP)
+			authedGroup.PUT("/users/me/leaderboard", userHandler.UpdateLeaderboardVisibility)
+			authedGroup.GET("/leaderboards", xpHandler.GetLeaderboard)
+			authedGroup.POST("/auth/stream-token", authHandler.IssueStreamToken)
 		}
 
 		studentGroup := api.Group("/student")
-		studentGroup.Use(middleware.AuthMiddleware())
+		studentGroup.Use(middleware.AuthMiddleware(authService))
 		studentGroup.Use(middleware.StudentMiddleware())
 		{
 			taskGroup := studentGroup.Group("/tasks")
 			{
 				taskGroup.POST("/", taskHandler.CreateTask)
 				taskGroup.GET("/", taskHandler.GetTasks)
+				taskGroup.POST("/recurring", taskHandler.CreateRecurringTask)
+				taskGroup.GET("/recurring", taskHandler.GetRecurringTasks)
+				taskGroup.GET("/calendar", taskHandler.GetCalendarSummary)
 				taskGroup.PUT("/:id", taskHandler.UpdateTask)
 				taskGroup.DELETE("/:id", taskHandler.DeleteTask)
 			}
 
 			materialGroup := studentGroup.Group("/materials")
 			{
-				materialGroup.POST("/pdf", aiHandler.IngestPDF)
-				materialGroup.POST("/youtube", aiHandler.IngestYouTube)
+				materialGroup.POST("/pdf", ingestionHandler.IngestPDF)
+				materialGroup.POST("/youtube", ingestionHandler.IngestYouTube)
+				materialGroup.GET("/jobs/:id", ingestionHandler.GetJob)
+				materialGroup.GET("/", materialHandler.GetMaterials)
+				materialGroup.GET("/:id", materialHandler.GetMaterial)
+				materialGroup.PATCH("/:id", materialHandler.RenameMaterial)
+				materialGroup.DELETE("/:id", materialHandler.DeleteMaterial)
+				materialGroup.POST("/:id/chat", materialChatHandler.Ask)
+				materialGroup.GET("/:id/chat", materialChatHandler.GetHistory)
+				materialGroup.DELETE("/:id/chat", materialChatHandler.ClearHistory)
 			}
 
 			aiGroup := studentGroup.Group("/ai")
 			{
 				aiGroup.POST("/summarize", aiHandler.GenerateSummary)
+				aiGroup.POST("/summarize/stream", aiHandler.StreamSummary)
+				aiGroup.GET("/summaries", aiHandler.GetSummaries)
 				aiGroup.POST("/quiz", aiHandler.GenerateQuiz)
+				aiGroup.POST("/flashcards", flashcardHandler.GenerateDeck)
+			}
+
+			flashcardGroup := studentGroup.Group("/flashcards")
+			{
+				flashcardGroup.GET("/due", flashcardHandler.GetDueCards)
+				flashcardGroup.POST("/:id/review", flashcardHandler.ReviewCard)
+			}
+
+			quizGroup := studentGroup.Group("/quizzes")
+			{
+				quizGroup.POST("/:id/attempts", quizHandler.StartAttempt)
+				quizGroup.GET("/attempts", quizHandler.GetAttemptHistory)
+				quizGroup.GET("/attempts/:id", quizHandler.GetAttempt)
+				quizGroup.POST("/attempts/:id/submit", quizHandler.SubmitAttempt)
+			}
+
+			focusGroup := studentGroup.Group("/focus")
+			{
+				focusGroup.POST("/start", focusHandler.StartSession)
+				focusGroup.GET("/active", focusHandler.GetActiveSession)
+				focusGroup.GET("/", focusHandler.GetSessions)
+				focusGroup.GET("/stats", focusHandler.GetStats)
+				focusGroup.POST("/:id/pause", focusHandler.PauseSession)
+				focusGroup.POST("/:id/resume", focusHandler.ResumeSession)
+				focusGroup.POST("/:id/stop", focusHandler.StopSession)
+			}
+
+			roomGroup := studentGroup.Group("/rooms")
+			{
+				roomGroup.POST("/", roomHandler.CreateRoom)
+				roomGroup.GET("/", roomHandler.GetRooms)
+				roomGroup.GET("/:id", roomHandler.GetRoomState)
+				roomGroup.DELETE("/:id", roomHandler.DeleteRoom)
+				roomGroup.POST("/:id/status", roomHandler.UpdateStatus)
+				roomGroup.POST("/:id/timer/start", roomHandler.StartTimer)
+				roomGroup.POST("/:id/timer/stop", roomHandler.StopTimer)
 			}
 
 			streakGroup := studentGroup.Group("/streaks")
 			{
 				streakGroup.POST("/check", userHandler.CheckAndUpdateStreak)
+				streakGroup.GET("/history", userHandler.GetStreakHistory)
 			}
+
+			studentGroup.POST("/pembimbing", mentorHandler.LinkStudent)
+		}
+
+		// EventSource tidak bisa mengirim header Authorization, jadi stream
+		// room memakai stream token berumur pendek lewat query ?token=.
+		roomStreamGroup := api.Group("/student/rooms")
+		roomStreamGroup.Use(middleware.StreamTokenMiddleware(authService))
+		roomStreamGroup.Use(middleware.StudentMiddleware())
+		{
+			roomStreamGroup.GET("/:id/stream", roomHandler.Stream)
+		}
+
+		mentorGroup := api.Group("/pembimbing")
+		mentorGroup.Use(middleware.AuthMiddleware(authService))
+		mentorGroup.Use(middleware.MentorMiddleware())
+		{
+			mentorGroup.GET("/kode", mentorHandler.GetCode)
+			mentorGroup.POST("/kode", mentorHandler.GenerateCode)
+			mentorGroup.GET("/siswa", mentorHandler.GetStudents)
+			mentorGroup.POST("/tasks", mentorHandler.AssignTask)
+			mentorGroup.GET("/tasks", mentorHandler.GetAssignedTasks)
 		}
 
 		adminGroup := api.Group("/admin")
-		adminGroup.Use(middleware.AuthMiddleware())
+		adminGroup.Use(middleware.AuthMiddleware(authService))
 		adminGroup.Use(middleware.AdminMiddleware())
 		{
 			adminGroup.GET("/users", userHandler.GetUsers)
 			adminGroup.GET("/users/:id", userHandler.GetUserByID)
 			adminGroup.PUT("/users/:id", userHandler.UpdateUser)
 			adminGroup.DELETE("/users/:id", userHandler.DeleteUser)
+			adminGroup.POST("/streaks/run", streakHandler.TriggerRun)
+			adminGroup.GET("/streaks/runs", streakHandler.GetRuns)
+			adminGroup.GET("/badges", achievementHandler.GetBadges)
+			adminGroup.POST("/badges", achievementHandler.CreateBadge)
+			adminGroup.PUT("/badges/:id", achievementHandler.UpdateBadge)
+			adminGroup.DELETE("/badges/:id", achievementHandler.DeleteBadge)
 		}
 	}
 
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Domain event yang memicu evaluasi achievement.
+const (
+	AchievementEventTaskCompleted = "task_completed"
+	AchievementEventQuizSubmitted = "quiz_submitted"
+	AchievementEventFocusEnded    = "focus_session_ended"
+)
+
+// achievementEventMetrics memetakan event ke metrik yang mungkin berubah
+// karenanya, supaya satu event hanya mengevaluasi badge yang relevan.
+var achievementEventMetrics = map[string][]string{
+	AchievementEventTaskCompleted: {model.BadgeMetricTasksCompleted, model.BadgeMetricStreakDays},
+	AchievementEventQuizSubmitted: {model.BadgeMetricQuizzesCompleted, model.BadgeMetricPerfectQuizzes},
+	AchievementEventFocusEnded:    {model.BadgeMetricFocusMinutes},
+}
+
+var allBadgeMetrics = []string{
+	model.BadgeMetricStreakDays,
+	model.BadgeMetricTasksCompleted,
+	model.BadgeMetricQuizzesCompleted,
+	model.BadgeMetricPerfectQuizzes,
+	model.BadgeMetricFocusMinutes,
+}
+
+// AchievementService mengevaluasi definisi badge terhadap statistik user.
+// Badge yang baru dibuat admin akan diberikan pada event berikutnya dari user
+// yang sudah memenuhi syarat.
+type AchievementService struct {
+	badgeRepo *repository.BadgeRepository
+	userRepo  *repository.UserRepository
+	taskRepo  *repository.TaskRepository
+	quizRepo  *repository.QuizRepository
+	focusRepo *repository.FocusSessionRepository
+}
+
+func NewAchievementService(badgeRepo *repository.BadgeRepository, userRepo *repository.UserRepository, taskRepo *repository.TaskRepository, quizRepo *repository.QuizRepository, focusRepo *repository.FocusSessionRepository) *AchievementService {
+	return &AchievementService{badgeRepo: badgeRepo, userRepo: userRepo, taskRepo: taskRepo, quizRepo: quizRepo, focusRepo: focusRepo}
+}
+
+// Publish mengevaluasi badge yang terkait event untuk user. Kegagalan hanya
+// di-log karena achievement tidak boleh menggagalkan aksi utama user.
+func (s *AchievementService) Publish(userID uint, event string) {
+	metrics, ok := achievementEventMetrics[event]
+	if !ok {
+		return
+	}
+
+	badges, err := s.badgeRepo.FindActiveByMetrics(metrics)
+	if err != nil {
+		log.Printf("[Achievement] Gagal mengambil badge untuk event %s: %v", event, err)
+		return
+	}
+
+	earned, err := s.earnedBadges(userID)
+	if err != nil {
+		log.Printf("[Achievement] Gagal mengambil badge user %d: %v", userID, err)
+		return
+	}
+
+	values := map[string]int{}
+	now := time.Now()
+	for _, badge := range badges {
+		if _, ok := earned[badge.ID]; ok {
+			continue
+		}
+
+		value, ok := values[badge.Metric]
+		if !ok {
+			value, err = s.metricValue(userID, badge.Metric)
+			if err != nil {
+				log.Printf("[Achievement] Gagal menghitung %s user %d: %v", badge.Metric, userID, err)
+				continue
+			}
+			values[badge.Metric] = value
+		}
+		if value < badge.Threshold {
+			continue
+		}
+
+		awarded, err := s.badgeRepo.Award(userID, badge.ID, now)
+		if err != nil {
+			log.Printf("[Achievement] Gagal menyimpan badge %s user %d: %v", badge.Code, userID, err)
+			continue
+		}
+		if awarded {
+			log.Printf("[Achievement] User %d mendapat badge %s.", userID, badge.Code)
+		}
+	}
+}
+
+func (s *AchievementService) GetMyAchievements(userIDString string) ([]dto.AchievementResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	badges, err := s.badgeRepo.FindActiveByMetrics(allBadgeMetrics)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data badge")
+	}
+
+	earned, err := s.earnedBadges(uint(userID))
+	if err != nil {
+		return nil, errors.New("gagal mengambil data badge")
+	}
+
+	values := map[string]int{}
+	responses := []dto.AchievementResponse{}
+	for _, badge := range badges {
+		value, ok := values[badge.Metric]
+		if !ok {
+			value, err = s.metricValue(uint(userID), badge.Metric)
+			if err != nil {
+				return nil, errors.New("gagal menghitung progres achievement")
+			}
+			values[badge.Metric] = value
+		}
+
+		response := dto.AchievementResponse{
+			BadgeResponse: badgeToResponse(&badge),
+			Progress:      min(value, badge.Threshold),
+		}
+		if earnedAt, ok := earned[badge.ID]; ok {
+			response.Earned = true
+			response.EarnedAt = &earnedAt
+			response.Progress = badge.Threshold
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+func (s *AchievementService) GetBadges() ([]dto.BadgeResponse, error) {
+	badges, err := s.badgeRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("gagal mengambil data badge")
+	}
+
+	responses := []dto.BadgeResponse{}
+	for _, badge := range badges {
+		responses = append(responses, badgeToResponse(&badge))
+	}
+	return responses, nil
+}
+
+func (s *AchievementService) CreateBadge(req dto.BadgeRequest) (*dto.BadgeResponse, error) {
+	if err := s.ensureCodeAvailable(req.Code, 0); err != nil {
+		return nil, err
+	}
+
+	badge := &model.Badge{IsActive: true}
+	applyBadgeRequest(badge, req)
+
+	createdBadge, err := s.badgeRepo.Create(badge)
+	if err != nil {
+		return nil, errors.New("gagal menyimpan badge")
+	}
+
+	response := badgeToResponse(createdBadge)
+	return &response, nil
+}
+
+func (s *AchievementService) UpdateBadge(id uint, req dto.BadgeRequest) (*dto.BadgeResponse, error) {
+	badge, err := s.badgeRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("badge tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil data badge")
+	}
+
+	if err := s.ensureCodeAvailable(req.Code, badge.ID); err != nil {
+		return nil, err
+	}
+
+	applyBadgeRequest(badge, req)
+	updatedBadge, err := s.badgeRepo.Update(badge)
+	if err != nil {
+		return nil, errors.New("gagal menyimpan badge")
+	}
+
+	response := badgeToResponse(updatedBadge)
+	return &response, nil
+}
+
+// DeleteBadge menghapus definisi badge beserta badge yang sudah didapat user.
+// Untuk menyembunyikan badge tanpa menghapus riwayat, set is_active false.
+func (s *AchievementService) DeleteBadge(id uint) error {
+	if _, err := s.badgeRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("badge tidak ditemukan")
+		}
+		return errors.New("gagal mengambil data badge")
+	}
+
+	if err := s.badgeRepo.Delete(id); err != nil {
+		return errors.New("gagal menghapus badge")
+	}
+	return nil
+}
+
+func (s *AchievementService) ensureCodeAvailable(code string, badgeID uint) error {
+	existing, err := s.badgeRepo.FindByCode(code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return errors.New("gagal mengambil data badge")
+	}
+	if existing.ID != badgeID {
+		return errors.New("kode badge sudah dipakai")
+	}
+	return nil
+}
+
+func (s *AchievementService) earnedBadges(userID uint) (map[uint]time.Time, error) {
+	userBadges, err := s.badgeRepo.FindUserBadges(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	earned := make(map[uint]time.Time, len(userBadges))
+	for _, userBadge := range userBadges {
+		earned[userBadge.BadgeID] = userBadge.EarnedAt
+	}
+	return earned, nil
+}
+
+func (s *AchievementService) metricValue(userID uint, metric string) (int, error) {
+	switch metric {
+	case model.BadgeMetricStreakDays:
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return 0, err
+		}
+		return max(user.LongestStreak, user.CurrentStreak), nil
+	case model.BadgeMetricTasksCompleted:
+		count, err := s.taskRepo.CountCompletedByUserID(userID)
+		return int(count), err
+	case model.BadgeMetricQuizzesCompleted:
+		count, err := s.quizRepo.CountSubmittedQuizzes(userID, 0)
+		return int(count), err
+	case model.BadgeMetricPerfectQuizzes:
+		count, err := s.quizRepo.CountSubmittedQuizzes(userID, 100)
+		return int(count), err
+	case model.BadgeMetricFocusMinutes:
+		seconds, err := s.focusRepo.SumEndedSeconds(userID)
+		return int(seconds / 60), err
+	}
+	return 0, errors.New("metrik badge tidak dikenal")
+}
+
+func applyBadgeRequest(badge *model.Badge, req dto.BadgeRequest) {
+	badge.Code = req.Code
+	badge.Name = req.Name
+	badge.Description = req.Description
+	badge.Metric = req.Metric
+	badge.Threshold = req.Threshold
+	if req.IsActive != nil {
+		badge.IsActive = *req.IsActive
+	}
+}
+
+func badgeToResponse(badge *model.Badge) dto.BadgeResponse {
+	return dto.BadgeResponse{
+		ID:          badge.ID,
+		Code:        badge.Code,
+		Name:        badge.Name,
+		Description: badge.Description,
+		Metric:      badge.Metric,
+		Threshold:   badge.Threshold,
+		IsActive:    badge.IsActive,
+	}
+}
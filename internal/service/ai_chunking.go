@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	materialChunkTokenBudget = 8000
+	maxParallelLLMCalls      = 4
+)
+
+func buildChunks(materialID uint, text string) []model.MaterialChunk {
+	var chunks []model.MaterialChunk
+	for i, content := range utils.ChunkText(text, materialChunkTokenBudget) {
+		chunks = append(chunks, model.MaterialChunk{
+			MaterialID: materialID,
+			ChunkIndex: i,
+			Content:    content,
+			TokenCount: utils.EstimateTokens(content),
+		})
+	}
+	return chunks
+}
+
+// ensureChunks mengambil potongan materi yang tersimpan, atau membuatnya
+// untuk materi lama yang di-ingest sebelum chunking ada.
+func (s *AIService) ensureChunks(material *model.Material) ([]model.MaterialChunk, error) {
+	chunks, err := s.matRepo.FindChunksByMaterialID(material.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil potongan materi: %w", err)
+	}
+	if len(chunks) > 0 {
+		return chunks, nil
+	}
+
+	chunks = buildChunks(material.ID, material.ExtractedText)
+	if len(chunks) == 0 {
+		return nil, errors.New("materi tidak memiliki teks untuk diproses")
+	}
+	if err := s.matRepo.SaveChunks(chunks); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan potongan materi: %w", err)
+	}
+
+	// Request lain bisa saja menyimpan chunk yang sama lebih dulu; yang
+	// dipakai selalu versi di database.
+	chunks, err = s.matRepo.FindChunksByMaterialID(material.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil potongan materi: %w", err)
+	}
+	return chunks, nil
+}
+
+// summarizeChunks menjalankan pipeline map-reduce: setiap potongan dirangkum
+// terpisah, lalu gabungan rangkumannya dijelaskan ulang dengan summaryPrompt.
+// Materi dengan satu potongan langsung memakai summaryPrompt. Jika onDelta
+// diisi, hanya tahap akhir yang di-stream karena rangkuman per potongan
+// bukan bagian dari hasil akhir.
+func (s *AIService) summarizeChunks(ctx context.Context, chunks []model.MaterialChunk, onDelta func(chunk string) error) (string, error) {
+	if len(chunks) == 1 {
+		return s.generateSummaryText(ctx, chunks[0].Content, onDelta)
+	}
+
+	log.Printf("[Summary] Merangkum %d potongan materi secara map-reduce...", len(chunks))
+
+	partials := make([]string, len(chunks))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxParallelLLMCalls)
+	for i, chunk := range chunks {
+		g.Go(func() error {
+			prompt := fmt.Sprintf(chunkSummaryPrompt, i+1, len(chunks), chunk.Content)
+			partial, err := s.llm.GenerateText(gctx, prompt)
+			if err != nil {
+				return err
+			}
+			if partial == "" {
+				return fmt.Errorf("LLM tidak memberikan rangkuman untuk bagian %d", i+1)
+			}
+			partials[i] = partial
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return "", err
+	}
+
+	var combined strings.Builder
+	for i, partial := range partials {
+		fmt.Fprintf(&combined, "Bagian %d:\n%s\n\n", i+1, partial)
+	}
+
+	return s.generateSummaryText(ctx, combined.String(), onDelta)
+}
+
+func (s *AIService) generateSummaryText(ctx context.Context, text string, onDelta func(chunk string) error) (string, error) {
+	var summary string
+	var err error
+	if onDelta != nil {
+		summary, err = s.llm.StreamText(ctx, fmt.Sprintf(summaryPrompt, text), onDelta)
+	} else {
+		summary, err = s.llm.GenerateText(ctx, fmt.Sprintf(summaryPrompt, text))
+	}
+	if err != nil {
+		return "", err
+	}
+	if summary == "" {
+		return "", errors.New("LLM tidak memberikan rangkuman")
+	}
+	return summary, nil
+}
+
+// generateQuizAcrossChunks membagi soal ke potongan yang dipilih merata dari
+// awal sampai akhir materi, dengan tipe soal bergiliran agar setiap potongan
+// mendapat campuran tipe, lalu menomori ulang hasilnya.
+func (s *AIService) generateQuizAcrossChunks(ctx context.Context, chunks []model.MaterialChunk, mix map[string]int, difficulty string) ([]dto.QuizQuestion, int, error) {
+	selected := sampleChunkIndexes(len(chunks), quizMixTotal(mix))
+
+	perChunk := make([]map[string]int, len(selected))
+	for i := range perChunk {
+		perChunk[i] = map[string]int{}
+	}
+	for i, questionType := range interleaveQuizTypes(mix) {
+		perChunk[i%len(selected)][questionType]++
+	}
+
+	results := make([][]dto.QuizQuestion, len(selected))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxParallelLLMCalls)
+	for i, chunkIndex := range selected {
+		g.Go(func() error {
+			questions, err := s.generateQuizQuestions(gctx, chunks[chunkIndex].Content, perChunk[i], difficulty)
+			if err != nil {
+				return err
+			}
+			results[i] = questions
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, 0, err
+	}
+
+	var questions []dto.QuizQuestion
+	for _, result := range results {
+		for _, q := range result {
+			q.ID = len(questions) + 1
+			questions = append(questions, q)
+		}
+	}
+
+	return questions, len(selected), nil
+}
+
+// sampleChunkIndexes memilih paling banyak n indeks yang tersebar merata
+// di antara total potongan.
+func sampleChunkIndexes(total int, n int) []int {
+	if n > total {
+		n = total
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i * total / n
+	}
+	return indexes
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"golang.org/x/sync/errgroup"
+)
+
+const flashcardPrompt = `Buatkan %d kartu flashcard berdasarkan materi berikut.
+
+Hasilkan dalam format JSON array yang valid. Setiap objek memiliki struktur:
+
+{
+  "depan": "string",   // pertanyaan, istilah, atau konsep yang diuji
+  "belakang": "string" // jawaban atau penjelasan singkat
+}
+
+Instruksi penting:
+1. Satu kartu hanya menguji satu fakta atau konsep.
+2. Sisi depan harus bisa dijawab tanpa melihat kartu lain.
+3. Sisi belakang singkat dan padat, maksimal dua kalimat.
+4. Gunakan bahasa Indonesia yang natural dan jelas.
+5. Jangan tambahkan penjelasan atau teks apa pun di luar format JSON.
+
+Materi:
+%s`
+
+// GenerateFlashcardItems membuat kartu dari potongan materi yang dipilih
+// merata, sama seperti pembuatan kuis. Hasil kedua adalah jumlah potongan
+// yang dipakai, hasil ketiga total potongan materi.
+func (s *AIService) GenerateFlashcardItems(ctx context.Context, material *model.Material, cardCount int) ([]dto.FlashcardItem, int, int, error) {
+	chunks, err := s.ensureChunks(material)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	selected := sampleChunkIndexes(len(chunks), cardCount)
+	perChunk := make([]int, len(selected))
+	for i := 0; i < cardCount; i++ {
+		perChunk[i%len(selected)]++
+	}
+
+	results := make([][]dto.FlashcardItem, len(selected))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxParallelLLMCalls)
+	for i, chunkIndex := range selected {
+		g.Go(func() error {
+			items, err := s.generateFlashcards(gctx, chunks[chunkIndex].Content, perChunk[i])
+			if err != nil {
+				return err
+			}
+			results[i] = items
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	var items []dto.FlashcardItem
+	for _, result := range results {
+		for _, item := range result {
+			item.Depan = strings.TrimSpace(item.Depan)
+			item.Belakang = strings.TrimSpace(item.Belakang)
+			if item.Depan == "" || item.Belakang == "" {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil, 0, 0, errors.New("LLM tidak memberikan flashcard")
+	}
+
+	return items, len(selected), len(chunks), nil
+}
+
+func (s *AIService) generateFlashcards(ctx context.Context, text string, count int) ([]dto.FlashcardItem, error) {
+	cardsJSON, err := s.llm.GenerateJSON(ctx, fmt.Sprintf(flashcardPrompt, count, text), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cardsJSON = strings.TrimSpace(cardsJSON)
+	cardsJSON = strings.TrimPrefix(cardsJSON, "```json")
+	cardsJSON = strings.TrimSuffix(cardsJSON, "```")
+
+	var items []dto.FlashcardItem
+	if err := json.Unmarshal([]byte(cardsJSON), &items); err != nil {
+		return nil, fmt.Errorf("gagal parsing hasil LLM: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+)
+
+// quizGenerationAttempts adalah jumlah maksimal panggilan LLM untuk satu
+// kelompok soal. Setelah percobaan pertama, prompt diberi koreksi berisi
+// aturan yang dilanggar.
+const quizGenerationAttempts = 3
+
+// maxShortAnswerLength menjaga jawaban short_answer dan fill_blank tetap
+// berupa kata atau frasa yang bisa dicocokkan.
+const maxShortAnswerLength = 100
+
+const quizCorrectionPrompt = `%s
+
+Jawaban kamu sebelumnya tidak bisa dipakai karena: %s.
+
+Jawaban sebelumnya:
+%s
+
+Perbaiki kesalahan tersebut dan kirim ulang HANYA JSON array berisi %d soal sesuai format di atas.`
+
+var quizOptionLetters = []string{"A", "B", "C", "D"}
+
+var quizBlankPattern = regexp.MustCompile(`_{3,}`)
+
+var quizSchema = &JSONSchema{
+	Type: "array",
+	Items: &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"id":         {Type: "integer"},
+			"tipe":       {Type: "string", Enum: quizTypes},
+			"pertanyaan": {Type: "string"},
+			"pilihan": {
+				Type: "array",
+				Items: &JSONSchema{
+					Type: "object",
+					Properties: map[string]*JSONSchema{
+						"A": {Type: "string"},
+						"B": {Type: "string"},
+						"C": {Type: "string"},
+						"D": {Type: "string"},
+					},
+				},
+			},
+			"jawaban_benar":      {Type: "string"},
+			"jawaban_alternatif": {Type: "array", Items: &JSONSchema{Type: "string"}},
+		},
+		Required: []string{"id", "tipe", "pertanyaan", "jawaban_benar"},
+	},
+}
+
+// generateQuizQuestions meminta soal sesuai komposisi mix untuk satu
+// potongan materi dan memvalidasi hasilnya. Output yang tidak valid dicoba
+// ulang dengan prompt koreksi; jika tetap gagal, error menyebutkan aturan
+// yang dilanggar.
+func (s *AIService) generateQuizQuestions(ctx context.Context, text string, mix map[string]int, difficulty string) ([]dto.QuizQuestion, error) {
+	count := quizMixTotal(mix)
+
+	var composition strings.Builder
+	for _, questionType := range quizTypes {
+		if mix[questionType] > 0 {
+			fmt.Fprintf(&composition, "- %d soal %s: %s\n", mix[questionType], questionType, quizTypeGuides[questionType])
+		}
+	}
+
+	basePrompt := fmt.Sprintf(quizPrompt, count, composition.String(), quizDifficultyGuides[difficulty], text)
+	prompt := basePrompt
+
+	var lastErr error
+	for attempt := 1; attempt <= quizGenerationAttempts; attempt++ {
+		quizJSON, err := s.llm.GenerateJSON(ctx, prompt, quizSchema)
+		if err != nil {
+			return nil, err
+		}
+
+		questions, err := parseQuizQuestions(quizJSON, mix)
+		if err == nil {
+			return questions, nil
+		}
+
+		lastErr = err
+		log.Printf("[Quiz] Output LLM tidak valid (percobaan %d/%d): %v", attempt, quizGenerationAttempts, err)
+		prompt = fmt.Sprintf(quizCorrectionPrompt, basePrompt, err, snippet(quizJSON, 4000), count)
+	}
+
+	return nil, fmt.Errorf("kuis dari LLM tidak valid setelah %d percobaan: %w", quizGenerationAttempts, lastErr)
+}
+
+// parseQuizQuestions mengambil JSON array dari output LLM (mengabaikan code
+// fence atau teks di sekitarnya) lalu memvalidasi setiap soal. Jumlah soal per
+// tipe harus tepat sama dengan mix, kelebihan maupun kekurangan dianggap
+// tidak valid supaya prompt koreksi dijalankan.
+func parseQuizQuestions(raw string, mix map[string]int) ([]dto.QuizQuestion, error) {
+	start := strings.Index(raw, "[")
+	end := strings.LastIndex(raw, "]")
+	if start < 0 || end < start {
+		return nil, errors.New("output tidak berisi JSON array")
+	}
+
+	var parsed []dto.QuizQuestion
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("output bukan JSON array soal yang valid (%v)", err)
+	}
+
+	counts := map[string]int{}
+	questions := make([]dto.QuizQuestion, 0, quizMixTotal(mix))
+	for _, q := range parsed {
+		q.Tipe = strings.ToLower(strings.TrimSpace(q.Tipe))
+		if !slices.Contains(quizTypes, q.Tipe) {
+			return nil, fmt.Errorf("soal id %d: tipe %q tidak dikenal", q.ID, q.Tipe)
+		}
+		counts[q.Tipe]++
+		questions = append(questions, q)
+	}
+
+	for _, questionType := range quizTypes {
+		if counts[questionType] != mix[questionType] {
+			return nil, fmt.Errorf("jumlah soal %s %d, seharusnya %d", questionType, counts[questionType], mix[questionType])
+		}
+	}
+
+	seenIDs := map[int]bool{}
+	for i := range questions {
+		q := &questions[i]
+		if seenIDs[q.ID] {
+			return nil, fmt.Errorf("id soal %d dipakai lebih dari sekali", q.ID)
+		}
+		seenIDs[q.ID] = true
+
+		if err := validateQuizQuestion(q); err != nil {
+			return nil, fmt.Errorf("soal id %d (%s): %w", q.ID, q.Tipe, err)
+		}
+	}
+	return questions, nil
+}
+
+// validateQuizQuestion memeriksa soal sesuai tipenya dan merapikan jawaban
+// ke bentuk yang disimpan di model.QuizQuestion.
+func validateQuizQuestion(q *dto.QuizQuestion) error {
+	q.Pertanyaan = strings.TrimSpace(q.Pertanyaan)
+	if q.Pertanyaan == "" {
+		return errors.New("pertanyaan kosong")
+	}
+
+	switch q.Tipe {
+	case model.QuizTypeMultipleChoice:
+		if err := validateQuizOptions(q); err != nil {
+			return err
+		}
+		q.JawabanBenar = strings.ToUpper(strings.TrimSpace(q.JawabanBenar))
+		if !slices.Contains(quizOptionLetters, q.JawabanBenar) {
+			return fmt.Errorf("jawaban_benar %q harus salah satu huruf A, B, C, atau D", q.JawabanBenar)
+		}
+		q.JawabanAlternatif = nil
+
+	case model.QuizTypeMultipleAnswer:
+		if err := validateQuizOptions(q); err != nil {
+			return err
+		}
+		letters := answerLetters(q.JawabanBenar)
+		for _, letter := range letters {
+			if !slices.Contains(quizOptionLetters, letter) {
+				return fmt.Errorf("jawaban_benar berisi huruf %q, padahal hanya A, B, C, atau D yang boleh", letter)
+			}
+		}
+		if len(letters) < 2 {
+			return fmt.Errorf("jawaban_benar %q harus berisi minimal dua huruf dipisah koma", q.JawabanBenar)
+		}
+		q.JawabanBenar = strings.Join(letters, ",")
+		q.JawabanAlternatif = nil
+
+	case model.QuizTypeTrueFalse:
+		q.JawabanBenar = normalizeQuizAnswer(model.QuizTypeTrueFalse, q.JawabanBenar)
+		if q.JawabanBenar != "BENAR" && q.JawabanBenar != "SALAH" {
+			return fmt.Errorf(`jawaban_benar %q harus "benar" atau "salah"`, q.JawabanBenar)
+		}
+		q.Pilihan = nil
+		q.JawabanAlternatif = nil
+
+	case model.QuizTypeShortAnswer, model.QuizTypeFillBlank:
+		if q.Tipe == model.QuizTypeFillBlank {
+			q.Pertanyaan = quizBlankPattern.ReplaceAllString(q.Pertanyaan, "___")
+			if strings.Count(q.Pertanyaan, "___") != 1 {
+				return errors.New(`pertanyaan harus berisi tepat satu bagian kosong "___"`)
+			}
+		}
+
+		q.JawabanBenar = strings.TrimSpace(q.JawabanBenar)
+		if q.JawabanBenar == "" {
+			return errors.New("jawaban_benar kosong")
+		}
+		if utf8.RuneCountInString(q.JawabanBenar) > maxShortAnswerLength {
+			return fmt.Errorf("jawaban_benar lebih dari %d karakter, harus berupa kata atau frasa pendek", maxShortAnswerLength)
+		}
+
+		var alternatives []string
+		for _, alternative := range q.JawabanAlternatif {
+			alternative = strings.TrimSpace(alternative)
+			if alternative != "" && utf8.RuneCountInString(alternative) <= maxShortAnswerLength && !slices.Contains(alternatives, alternative) {
+				alternatives = append(alternatives, alternative)
+			}
+		}
+		q.JawabanAlternatif = alternatives
+		q.Pilihan = nil
+	}
+	return nil
+}
+
+// validateQuizOptions memastikan soal punya tepat empat pilihan A–D. Huruf
+// dan teks dirapikan, dan pilihan diurutkan dari A sampai D.
+func validateQuizOptions(q *dto.QuizQuestion) error {
+	if len(q.Pilihan) != len(quizOptionLetters) {
+		return fmt.Errorf("harus punya tepat 4 pilihan A–D, ditemukan %d", len(q.Pilihan))
+	}
+
+	optionTexts := map[string]string{}
+	for i, option := range q.Pilihan {
+		if len(option) != 1 {
+			return fmt.Errorf("pilihan ke-%d harus berisi tepat satu huruf dan teksnya", i+1)
+		}
+		for letter, text := range option {
+			letter = strings.ToUpper(strings.TrimSpace(letter))
+			if !slices.Contains(quizOptionLetters, letter) {
+				return fmt.Errorf("huruf pilihan %q bukan A, B, C, atau D", letter)
+			}
+			if _, exists := optionTexts[letter]; exists {
+				return fmt.Errorf("pilihan %s muncul lebih dari sekali", letter)
+			}
+			text = strings.TrimSpace(text)
+			if text == "" {
+				return fmt.Errorf("teks pilihan %s kosong", letter)
+			}
+			optionTexts[letter] = text
+		}
+	}
+
+	q.Pilihan = make([]dto.QuizOption, 0, len(quizOptionLetters))
+	for _, letter := range quizOptionLetters {
+		q.Pilihan = append(q.Pilihan, dto.QuizOption{letter: optionTexts[letter]})
+	}
+	return nil
+}
@@ -2,111 +2,160 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
-	"mime/multipart"
 	"strconv"
 	"strings"
 
-	"github.com/google/generative-ai-go/genai"
 	"github.com/mohamadarif03/focus-room-be/internal/dto"
 	"github.com/mohamadarif03/focus-room-be/internal/model"
 	"github.com/mohamadarif03/focus-room-be/internal/repository"
 	"github.com/mohamadarif03/focus-room-be/pkg/utils"
-	"google.golang.org/api/option"
+	"gorm.io/gorm"
 )
 
-type AIService struct {
-	geminiModel *genai.GenerativeModel
-	matRepo     *repository.MaterialRepository
+// summaryPromptVersion harus dinaikkan setiap kali prompt rangkuman diubah,
+// supaya rangkuman lama yang tersimpan tidak dipakai lagi sebagai cache.
+const summaryPromptVersion = "v2"
+
+const summaryPrompt = "Jelaskan ulang isi materi berikut secara jelas, mendalam, dan terstruktur, seperti seorang dosen profesional yang menjelaskan konsep di kelas, namun tanpa sapaan pembuka atau penutup kelas (misalnya: “Selamat pagi mahasiswa”, “Apakah ada pertanyaan?”, dan sejenisnya). Saat menjelaskan ulang: Gunakan bahasa yang natural, komunikatif, dan logis, bukan formal kaku. Fokus untuk memperjelas isi materi, bukan sekadar merangkum. Jelaskan konsep dan ide utama dengan contoh nyata atau analogi jika perlu. Jika ada istilah sulit, jelaskan maknanya terlebih dahulu sebelum lanjut. Gunakan gaya penjelasan yang mengalir seperti narasi dosen yang fokus menjelaskan isi (tanpa salam, tanpa tanya jawab). Tutup dengan ringkasan inti dan kesimpulan, bukan kalimat interaktif seperti “ada pertanyaan?” atau “sampai jumpa”. Output yang diharapkan: Penjelasan ulang yang runtut, detail, dan mudah dipahami Gaya profesional namun tetap natural Tidak ada bagian sapaan, humor, atau tanya-jawab interaktif. Materi:\n\n%s"
+
+const quizPrompt = `Buatkan %d soal latihan berdasarkan materi berikut dengan komposisi:
+%s
+Tingkat kesulitan: %s
+
+Hasilkan dalam format JSON array yang valid dan rapi.
+Setiap objek di dalam array harus memiliki struktur berikut:
+
+{
+  "id": number,
+  "tipe": "string", // salah satu tipe pada komposisi di atas
+  "pertanyaan": "string",
+  "pilihan": [
+    {"A": "string"},
+    {"B": "string"},
+    {"C": "string"},
+    {"D": "string"}
+  ], // hanya untuk multiple_choice dan multiple_answer
+  "jawaban_benar": "string",
+  "jawaban_alternatif": ["string"] // hanya untuk short_answer dan fill_blank
 }
 
-func NewAIService(apiKey string, matRepo *repository.MaterialRepository) (*AIService, error) {
-	if apiKey == "" {
-		return nil, errors.New("GEMINI_API_KEY tidak ditemukan")
-	}
+Instruksi penting:
+1. Soal harus relevan langsung dengan isi materi dan menguji pemahaman konsep (bukan hafalan).
+2. Setiap opsi jawaban harus masuk akal dan proporsional, tidak terlalu mudah ditebak.
+3. Hindari pola yang membuat jawaban benar selalu mudah dikenali, seperti:
+   - jawaban paling panjang atau paling detail,
+   - posisi jawaban benar selalu sama.
+4. Gunakan bahasa Indonesia yang natural dan jelas, seperti soal buatan manusia.
+5. Sesuaikan seluruh soal dengan tingkat kesulitan di atas.
+6. Jangan tambahkan penjelasan, pembuka, atau teks apa pun di luar format JSON.
+7. Pastikan output adalah JSON yang valid dan bisa langsung di-parse tanpa error.
+8. Ikuti format "jawaban_benar" sesuai aturan tipe soal pada komposisi di atas.
 
-	ctx := context.Background()
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
-	if err != nil {
-		return nil, err
-	}
+Materi:
+%s`
+
+const chunkSummaryPrompt = `Rangkum bagian %d dari %d sebuah materi berikut secara padat namun lengkap. Pertahankan konsep utama, definisi, rumus, contoh, dan istilah penting apa adanya agar bisa digabung dengan rangkuman bagian lain. Jangan tambahkan pembuka, penutup, atau komentar di luar isi materi.
+
+Bagian materi:
 
-	model := client.GenerativeModel("gemini-2.5-flash")
-	return &AIService{geminiModel: model, matRepo: matRepo}, nil
+%s`
+
+type AIService struct {
+	llm         LLMProvider
+	matRepo     *repository.MaterialRepository
+	quizRepo    *repository.QuizRepository
+	summaryRepo *repository.SummaryRepository
 }
 
-func (s *AIService) IngestPDF(ctx context.Context, fileHeader *multipart.FileHeader, title string, userIDString string) (*dto.MaterialResponse, error) {
-	file, err := fileHeader.Open()
-	if err != nil {
-		return nil, errors.New("gagal membuka file")
-	}
-	defer file.Close()
+func NewAIService(llm LLMProvider, matRepo *repository.MaterialRepository, quizRepo *repository.QuizRepository, summaryRepo *repository.SummaryRepository) *AIService {
+	return &AIService{llm: llm, matRepo: matRepo, quizRepo: quizRepo, summaryRepo: summaryRepo}
+}
 
-	rawText, err := utils.ExtractTextFromPDF(file, fileHeader.Size)
+func (s *AIService) IngestPDF(ctx context.Context, data []byte, filename string, title string, userID uint) (*model.Material, error) {
+	pages, err := utils.ExtractPDFPages(ctx, data)
 	if err != nil {
 		return nil, fmt.Errorf("gagal ekstrak PDF: %w", err)
 	}
-	if rawText == "" {
+	rawText := strings.Join(pages, "\n")
+	if strings.TrimSpace(rawText) == "" {
 		return nil, errors.New("PDF ini tidak mengandung teks")
 	}
 
-	userID, _ := strconv.ParseUint(userIDString, 10, 32)
 	newMaterial := &model.Material{
-		UserID:        uint(userID),
+		UserID:        userID,
 		Title:         title,
 		SourceType:    "pdf",
-		Source:        fileHeader.Filename,
+		Source:        filename,
 		ExtractedText: rawText,
 	}
-	savedMat, err := s.matRepo.Save(newMaterial)
+	chunks := buildChunks(0, rawText)
+	passages := buildPassages(0, pdfPassageSources(pages))
+	// Job yang sudah melewati batas waktu tidak boleh lagi menyimpan materi,
+	// karena job itu bisa sudah diambil ulang worker lain.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	savedMat, err := s.matRepo.SaveWithContent(newMaterial, chunks, passages)
 	if err != nil {
 		return nil, fmt.Errorf("gagal menyimpan materi: %w", err)
 	}
 
 	log.Println("PDF Ingested, ID:", savedMat.ID)
-	return &dto.MaterialResponse{
-		ID:         savedMat.ID,
-		Title:      savedMat.Title,
-		SourceType: savedMat.SourceType,
-		Source:     savedMat.Source,
-	}, nil
+	return savedMat, nil
 }
 
-func (s *AIService) IngestYouTube(ctx context.Context, req dto.IngestYouTubeRequest, userIDString string) (*dto.MaterialResponse, error) {
-	rawText, err := utils.ExtractTextFromYouTube(req.URL)
+func (s *AIService) IngestYouTube(ctx context.Context, youtubeURL string, title string, userID uint) (*model.Material, error) {
+	segments, err := utils.ExtractYouTubeSegments(ctx, youtubeURL)
 	if err != nil {
 		return nil, err
 	}
-	if rawText == "" {
+	var transcript strings.Builder
+	for _, segment := range segments {
+		transcript.WriteString(segment.Text)
+		transcript.WriteString(" ")
+	}
+	rawText := transcript.String()
+	if strings.TrimSpace(rawText) == "" {
 		return nil, errors.New("video ini tidak memiliki transkrip")
 	}
 
-	userID, _ := strconv.ParseUint(userIDString, 10, 32)
 	newMaterial := &model.Material{
-		UserID:        uint(userID),
-		Title:         req.Title,
+		UserID:        userID,
+		Title:         title,
 		SourceType:    "youtube",
-		Source:        req.URL,
+		Source:        youtubeURL,
 		ExtractedText: rawText,
 	}
-	savedMat, err := s.matRepo.Save(newMaterial)
+	chunks := buildChunks(0, rawText)
+	passages := buildPassages(0, transcriptPassageSources(segments))
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	savedMat, err := s.matRepo.SaveWithContent(newMaterial, chunks, passages)
 	if err != nil {
 		return nil, fmt.Errorf("gagal menyimpan materi: %w", err)
 	}
 
 	log.Println("YouTube Ingested, ID:", savedMat.ID)
-	return &dto.MaterialResponse{
-		ID:         savedMat.ID,
-		Title:      savedMat.Title,
-		SourceType: savedMat.SourceType,
-		Source:     savedMat.Source,
-	}, nil
+	return savedMat, nil
 }
 
 func (s *AIService) GenerateSummary(ctx context.Context, req dto.GenerateSummaryRequest, userIDString string) (*dto.GenerateSummaryResponse, error) {
+	return s.generateSummary(ctx, req, userIDString, nil)
+}
+
+// StreamSummary sama dengan GenerateSummary, tetapi teks rangkuman dikirim ke
+// onDelta sedikit demi sedikit selama LLM menulisnya. Rangkuman dari cache
+// tidak memicu onDelta. Jika ctx dibatalkan (misalnya client menutup
+// koneksi), pembuatan dihentikan dan tidak ada yang disimpan.
+func (s *AIService) StreamSummary(ctx context.Context, req dto.GenerateSummaryRequest, userIDString string, onDelta func(chunk string) error) (*dto.GenerateSummaryResponse, error) {
+	return s.generateSummary(ctx, req, userIDString, onDelta)
+}
+
+func (s *AIService) generateSummary(ctx context.Context, req dto.GenerateSummaryRequest, userIDString string, onDelta func(chunk string) error) (*dto.GenerateSummaryResponse, error) {
 	userID, _ := strconv.ParseUint(userIDString, 10, 32)
 
 	material, err := s.matRepo.FindByID(req.MaterialID, uint(userID))
@@ -114,103 +163,138 @@ func (s *AIService) GenerateSummary(ctx context.Context, req dto.GenerateSummary
 		return nil, errors.New("materi tidak ditemukan atau anda tidak punya akses")
 	}
 
-	prompt := fmt.Sprintf("Jelaskan ulang isi materi berikut secara jelas, mendalam, dan terstruktur, seperti seorang dosen profesional yang menjelaskan konsep di kelas, namun tanpa sapaan pembuka atau penutup kelas (misalnya: “Selamat pagi mahasiswa”, “Apakah ada pertanyaan?”, dan sejenisnya). Saat menjelaskan ulang: Gunakan bahasa yang natural, komunikatif, dan logis, bukan formal kaku. Fokus untuk memperjelas isi materi, bukan sekadar merangkum. Jelaskan konsep dan ide utama dengan contoh nyata atau analogi jika perlu. Jika ada istilah sulit, jelaskan maknanya terlebih dahulu sebelum lanjut. Gunakan gaya penjelasan yang mengalir seperti narasi dosen yang fokus menjelaskan isi (tanpa salam, tanpa tanya jawab). Tutup dengan ringkasan inti dan kesimpulan, bukan kalimat interaktif seperti “ada pertanyaan?” atau “sampai jumpa”. Output yang diharapkan: Penjelasan ulang yang runtut, detail, dan mudah dipahami Gaya profesional namun tetap natural Tidak ada bagian sapaan, humor, atau tanya-jawab interaktif. Materi:\n\n%s", material.ExtractedText)
-	resp, err := s.geminiModel.GenerateContent(ctx, genai.Text(prompt))
+	if !req.Regenerate {
+		cached, err := s.summaryRepo.FindLatest(material.ID, summaryPromptVersion)
+		if err == nil {
+			log.Printf("[Summary] Memakai rangkuman tersimpan untuk materi %d (versi %d).", material.ID, cached.Version)
+			response := summaryToResponse(cached)
+			response.Cached = true
+			return &response, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("gagal mengambil rangkuman tersimpan: %w", err)
+		}
+	}
+
+	chunks, err := s.ensureChunks(material)
 	if err != nil {
-		return nil, fmt.Errorf("gagal memanggil Gemini: %w", err)
+		return nil, err
 	}
 
-	var summary string
-	if len(resp.Candidates) > 0 {
-		for _, part := range resp.Candidates[0].Content.Parts {
-			if txt, ok := part.(genai.Text); ok {
-				summary += string(txt)
-			}
-		}
+	summary, err := s.summarizeChunks(ctx, chunks, onDelta)
+	if err != nil {
+		return nil, err
 	}
-	if summary == "" {
-		return nil, errors.New("Gemini tidak memberikan rangkuman")
+
+	savedSummary, err := s.summaryRepo.SaveNextVersion(&model.MaterialSummary{
+		MaterialID:    material.ID,
+		PromptVersion: summaryPromptVersion,
+		ChunkCount:    len(chunks),
+		Summary:       summary,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan rangkuman: %w", err)
 	}
 
-	return &dto.GenerateSummaryResponse{
-		MaterialID: req.MaterialID,
-		Summary:    summary,
-	}, nil
+	response := summaryToResponse(savedSummary)
+	return &response, nil
 }
 
-func (s *AIService) GenerateQuiz(ctx context.Context, req dto.GenerateQuizRequest, userIDString string) (*dto.GenerateQuizResponse, error) {
+func (s *AIService) GetSummaries(materialIDString string, userIDString string) ([]dto.GenerateSummaryResponse, error) {
 	userID, _ := strconv.ParseUint(userIDString, 10, 32)
+	materialID, err := strconv.ParseUint(materialIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("material ID tidak valid")
+	}
 
-	material, err := s.matRepo.FindByID(req.MaterialID, uint(userID))
+	material, err := s.matRepo.FindByID(uint(materialID), uint(userID))
 	if err != nil {
 		return nil, errors.New("materi tidak ditemukan atau anda tidak punya akses")
 	}
 
-	prompt := fmt.Sprintf(
-		`Buatkan %d soal latihan berdasarkan materi berikut.
+	summaries, err := s.summaryRepo.FindByMaterialID(material.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat rangkuman: %w", err)
+	}
 
-Hasilkan dalam format JSON array yang valid dan rapi.
-Setiap objek di dalam array harus memiliki struktur berikut:
+	responses := []dto.GenerateSummaryResponse{}
+	for _, summary := range summaries {
+		responses = append(responses, summaryToResponse(&summary))
+	}
+	return responses, nil
+}
 
-{
-  "id": number,
-  "pertanyaan": "string",
-  "pilihan": [
-    {"A": "string"},
-    {"B": "string"},
-    {"C": "string"},
-    {"D": "string"}
-  ],
-  "jawaban_benar": "string" // huruf A, B, C, atau D saja
+func summaryToResponse(summary *model.MaterialSummary) dto.GenerateSummaryResponse {
+	return dto.GenerateSummaryResponse{
+		MaterialID:    summary.MaterialID,
+		SummaryID:     summary.ID,
+		Version:       summary.Version,
+		PromptVersion: summary.PromptVersion,
+		ChunksCovered: summary.ChunkCount,
+		Summary:       summary.Summary,
+		CreatedAt:     summary.CreatedAt,
+	}
 }
 
-Instruksi penting:
-1. Soal harus relevan langsung dengan isi materi dan menguji pemahaman konsep (bukan hafalan).
-2. Setiap opsi jawaban harus masuk akal dan proporsional, tidak terlalu mudah ditebak.
-3. Hindari pola yang membuat jawaban benar selalu mudah dikenali, seperti:
-   - jawaban paling panjang atau paling detail,
-   - posisi jawaban benar selalu sama.
-4. Gunakan bahasa Indonesia yang natural dan jelas, seperti soal buatan manusia.
-5. Variasikan tingkat kesulitan: sebagian soal dasar, sebagian penerapan atau analisis.
-6. Jangan tambahkan penjelasan, pembuka, atau teks apa pun di luar format JSON.
-7. Pastikan output adalah JSON yang valid dan bisa langsung di-parse tanpa error.
-8. Nilai "jawaban_benar" hanya berisi huruf A, B, C, atau D — bukan teks jawaban.
+func (s *AIService) GenerateQuiz(ctx context.Context, req dto.GenerateQuizRequest, userIDString string) (*dto.GenerateQuizResponse, error) {
+	userID, _ := strconv.ParseUint(userIDString, 10, 32)
 
-Materi:
-%s`,
-		req.QuestionCount,
-		material.ExtractedText,
-	)
+	material, err := s.matRepo.FindByID(req.MaterialID, uint(userID))
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan atau anda tidak punya akses")
+	}
 
-	resp, err := s.geminiModel.GenerateContent(ctx, genai.Text(prompt))
+	mix, err := resolveQuizMix(req)
 	if err != nil {
-		return nil, fmt.Errorf("gagal memanggil Gemini: %w", err)
+		return nil, err
+	}
+	difficulty := req.Difficulty
+	if difficulty == "" {
+		difficulty = model.QuizDifficultyMedium
 	}
 
-	var quizJSON string
-	if len(resp.Candidates) > 0 {
-		for _, part := range resp.Candidates[0].Content.Parts {
-			if txt, ok := part.(genai.Text); ok {
-				quizJSON += string(txt)
-			}
-		}
+	chunks, err := s.ensureChunks(material)
+	if err != nil {
+		return nil, err
 	}
 
-	quizJSON = strings.TrimSpace(quizJSON)
-	quizJSON = strings.TrimPrefix(quizJSON, "```json")
-	quizJSON = strings.TrimSuffix(quizJSON, "```")
+	questions, chunksCovered, err := s.generateQuizAcrossChunks(ctx, chunks, mix, difficulty)
+	if err != nil {
+		return nil, err
+	}
 
-	if quizJSON == "" {
-		return nil, errors.New("Gemini tidak memberikan kuis")
+	quiz := &model.Quiz{
+		UserID:     uint(userID),
+		MaterialID: material.ID,
+		Difficulty: difficulty,
+	}
+	for _, q := range questions {
+		var pilihan []map[string]string
+		for _, opt := range q.Pilihan {
+			pilihan = append(pilihan, opt)
+		}
+		quiz.Questions = append(quiz.Questions, model.QuizQuestion{
+			Number:            q.ID,
+			Type:              q.Tipe,
+			Pertanyaan:        q.Pertanyaan,
+			Pilihan:           pilihan,
+			JawabanBenar:      q.JawabanBenar,
+			JawabanAlternatif: q.JawabanAlternatif,
+		})
 	}
 
-	var questions []dto.QuizQuestion
-	if err := json.Unmarshal([]byte(quizJSON), &questions); err != nil {
-		return nil, fmt.Errorf("gagal parsing hasil Gemini: %w", err)
+	savedQuiz, err := s.quizRepo.Save(quiz)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan kuis: %w", err)
 	}
 
+	log.Println("Quiz Generated, ID:", savedQuiz.ID)
 	return &dto.GenerateQuizResponse{
-		MaterialID: req.MaterialID,
-		Questions:  questions,
+		MaterialID:    req.MaterialID,
+		QuizID:        savedQuiz.ID,
+		Difficulty:    savedQuiz.Difficulty,
+		ChunksCovered: chunksCovered,
+		TotalChunks:   len(chunks),
+		Questions:     quizQuestionsToResponse(savedQuiz.Questions),
 	}, nil
 }
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"gorm.io/gorm"
+)
+
+const testMaterialText = "Fotosintesis adalah proses tumbuhan mengubah cahaya matahari, air, dan karbon dioksida menjadi glukosa dan oksigen."
+
+func newTestAIService(db *gorm.DB, llm LLMProvider) *AIService {
+	return NewAIService(llm, repository.NewMaterialRepository(db), repository.NewQuizRepository(db), repository.NewSummaryRepository(db))
+}
+
+// createTestMaterial membuat materi tanpa chunk, sama seperti materi lama,
+// sehingga pembuatan chunk ikut teruji.
+func createTestMaterial(t *testing.T, db *gorm.DB, userID uint) *model.Material {
+	t.Helper()
+	material := &model.Material{UserID: userID, Title: "Fotosintesis", SourceType: "pdf", Source: "fotosintesis.pdf", ExtractedText: testMaterialText}
+	if err := db.Omit("User").Create(material).Error; err != nil {
+		t.Fatalf("gagal membuat materi test: %v", err)
+	}
+	return material
+}
+
+func TestGenerateSummaryWithFakeLLM(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestStudent(t, db, nil)
+	material := createTestMaterial(t, db, user.ID)
+	userID := strconv.FormatUint(uint64(user.ID), 10)
+
+	llm := NewFakeLLMProvider()
+	llm.TextResponse = "Fotosintesis menghasilkan glukosa dan oksigen."
+	ai := newTestAIService(db, llm)
+
+	first, err := ai.GenerateSummary(context.Background(), dto.GenerateSummaryRequest{MaterialID: material.ID}, userID)
+	if err != nil {
+		t.Fatalf("GenerateSummary: %v", err)
+	}
+	if first.Cached || first.Version != 1 || first.ChunksCovered != 1 || first.Summary != llm.TextResponse {
+		t.Errorf("rangkuman pertama = %+v", first)
+	}
+	prompts := llm.Prompts()
+	if len(prompts) != 1 || !strings.Contains(prompts[0], testMaterialText) {
+		t.Errorf("prompt rangkuman tidak berisi teks materi: %q", prompts)
+	}
+
+	cached, err := ai.GenerateSummary(context.Background(), dto.GenerateSummaryRequest{MaterialID: material.ID}, userID)
+	if err != nil {
+		t.Fatalf("GenerateSummary dari cache: %v", err)
+	}
+	if !cached.Cached || cached.SummaryID != first.SummaryID {
+		t.Errorf("rangkuman kedua = %+v, want cache dari summary %d", cached, first.SummaryID)
+	}
+	if got := len(llm.Prompts()); got != 1 {
+		t.Errorf("LLM dipanggil %d kali, want 1", got)
+	}
+
+	regenerated, err := ai.GenerateSummary(context.Background(), dto.GenerateSummaryRequest{MaterialID: material.ID, Regenerate: true}, userID)
+	if err != nil {
+		t.Fatalf("GenerateSummary regenerate: %v", err)
+	}
+	if regenerated.Cached || regenerated.Version != 2 {
+		t.Errorf("rangkuman regenerate = %+v, want versi 2", regenerated)
+	}
+}
+
+func TestGenerateQuizWithFakeLLM(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestStudent(t, db, nil)
+	material := createTestMaterial(t, db, user.ID)
+	userID := strconv.FormatUint(uint64(user.ID), 10)
+
+	ai := newTestAIService(db, NewFakeLLMProvider())
+	req := dto.GenerateQuizRequest{
+		MaterialID: material.ID,
+		Difficulty: model.QuizDifficultyHard,
+		TypeMix:    map[string]int{model.QuizTypeMultipleChoice: 2, model.QuizTypeTrueFalse: 1},
+	}
+
+	response, err := ai.GenerateQuiz(context.Background(), req, userID)
+	if err != nil {
+		t.Fatalf("GenerateQuiz: %v", err)
+	}
+	if response.QuizID == 0 || response.Difficulty != model.QuizDifficultyHard || response.TotalChunks != 1 {
+		t.Errorf("response kuis = %+v", response)
+	}
+
+	counts := map[string]int{}
+	for _, question := range response.Questions {
+		counts[question.Tipe]++
+	}
+	if counts[model.QuizTypeMultipleChoice] != 2 || counts[model.QuizTypeTrueFalse] != 1 || len(response.Questions) != 3 {
+		t.Errorf("komposisi soal = %v, want 2 multiple_choice dan 1 true_false", counts)
+	}
+
+	quiz, err := repository.NewQuizRepository(db).FindByID(response.QuizID, user.ID)
+	if err != nil {
+		t.Fatalf("kuis tidak tersimpan: %v", err)
+	}
+	if len(quiz.Questions) != 3 {
+		t.Errorf("jumlah soal tersimpan = %d, want 3", len(quiz.Questions))
+	}
+	for _, question := range quiz.Questions {
+		if question.JawabanBenar == "" {
+			t.Errorf("soal %d tidak punya jawaban benar", question.Number)
+		}
+	}
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"errors"
+	"log"
+	"strconv"
+	"time"
 
 	"github.com/mohamadarif03/focus-room-be/internal/dto"
 	"github.com/mohamadarif03/focus-room-be/internal/model"
@@ -11,11 +14,13 @@ import (
 )
 
 type AuthService struct {
-	userRepo *repository.UserRepository
+	userRepo    *repository.UserRepository
+	refreshRepo *repository.RefreshTokenRepository
+	refreshTTL  time.Duration
 }
 
-func NewAuthService(userRepo *repository.UserRepository) *AuthService {
-	return &AuthService{userRepo: userRepo}
+func NewAuthService(userRepo *repository.UserRepository, refreshRepo *repository.RefreshTokenRepository, refreshTTL time.Duration) *AuthService {
+	return &AuthService{userRepo: userRepo, refreshRepo: refreshRepo, refreshTTL: refreshTTL}
 }
 
 func (s *AuthService) Register(req dto.RegisterRequest) (*dto.AuthResponse, error) {
@@ -27,6 +32,14 @@ func (s *AuthService) Register(req dto.RegisterRequest) (*dto.AuthResponse, erro
 		return nil, errors.New("email already registered")
 	}
 
+	timeZone := DefaultTimeZone
+	if req.TimeZone != "" {
+		if _, err := LoadTimeZone(req.TimeZone); err != nil {
+			return nil, err
+		}
+		timeZone = req.TimeZone
+	}
+
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
 		return nil, errors.New("failed to hash password")
@@ -37,6 +50,7 @@ func (s *AuthService) Register(req dto.RegisterRequest) (*dto.AuthResponse, erro
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
 		Role:         req.Role,
+		TimeZone:     timeZone,
 	}
 
 	createdUser, err := s.userRepo.CreateUser(&newUser)
@@ -44,19 +58,11 @@ func (s *AuthService) Register(req dto.RegisterRequest) (*dto.AuthResponse, erro
 		return nil, errors.New("failed to create user")
 	}
 
-	token, err := utils.GenerateToken(createdUser.ID, createdUser.Role)
+	response, err := s.issueTokens(createdUser)
 	if err != nil {
 		return nil, errors.New("failed to generate token")
 	}
 
-	response := &dto.AuthResponse{
-		ID:       createdUser.ID,
-		Username: createdUser.Username,
-		Email:    createdUser.Email,
-		Role:     createdUser.Role,
-		Token:    token,
-	}
-
 	return response, nil
 }
 
@@ -74,18 +80,169 @@ func (s *AuthService) Login(req dto.LoginRequest) (*dto.AuthResponse, error) {
 		return nil, errors.New("email atau password salah")
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.Role)
+	response, err := s.issueTokens(user)
 	if err != nil {
 		return nil, errors.New("gagal membuat token")
 	}
 
-	response := &dto.AuthResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Role:     user.Role,
-		Token:    token,
+	return response, nil
+}
+
+// Refresh menukar refresh token dengan pasangan token baru. Refresh token
+// lama langsung dicabut; jika token yang sudah dicabut dipakai lagi, semua
+// sesi user dianggap bocor dan ikut dicabut.
+func (s *AuthService) Refresh(req dto.RefreshTokenRequest) (*dto.AuthResponse, error) {
+	stored, err := s.refreshRepo.FindByHash(utils.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refresh token tidak valid")
+		}
+		return nil, errors.New("database error")
+	}
+
+	if stored.RevokedAt != nil {
+		log.Printf("[Auth] Refresh token lama user %d dipakai ulang. Semua sesi dicabut.", stored.UserID)
+		s.revokeAllSessions(stored.UserID)
+		return nil, errors.New("refresh token tidak valid")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.New("refresh token tidak valid")
+	}
+
+	revoked, err := s.refreshRepo.Revoke(stored.ID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if !revoked {
+		log.Printf("[Auth] Refresh token user %d dipakai bersamaan. Semua sesi dicabut.", stored.UserID)
+		s.revokeAllSessions(stored.UserID)
+		return nil, errors.New("refresh token tidak valid")
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil {
+		return nil, errors.New("refresh token tidak valid")
+	}
+
+	response, newTokenID, err := s.issueTokensWithID(user)
+	if err != nil {
+		return nil, errors.New("gagal membuat token")
+	}
+
+	if err := s.refreshRepo.SetReplacedBy(stored.ID, newTokenID); err != nil {
+		log.Printf("[Auth] Gagal mencatat rotasi refresh token %d: %v", stored.ID, err)
 	}
 
 	return response, nil
 }
+
+func (s *AuthService) Logout(req dto.LogoutRequest) error {
+	stored, err := s.refreshRepo.FindByHash(utils.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("refresh token tidak valid")
+		}
+		return errors.New("database error")
+	}
+
+	if req.AllDevices {
+		return s.revokeAllSessions(stored.UserID)
+	}
+
+	if _, err := s.refreshRepo.Revoke(stored.ID); err != nil {
+		return errors.New("gagal logout")
+	}
+	return nil
+}
+
+// CheckTokenVersion memastikan access token masih berlaku untuk user yang
+// bersangkutan: user masih ada dan token diterbitkan setelah TokenVersion
+// terakhir (yang naik saat logout semua perangkat atau role berubah).
+func (s *AuthService) CheckTokenVersion(userIDString string, tokenVersion int) error {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return errors.New("user ID tidak valid")
+	}
+
+	user, err := s.userRepo.FindByID(uint(userID))
+	if err != nil {
+		return errors.New("user tidak ditemukan")
+	}
+
+	if tokenVersion < user.TokenVersion {
+		return errors.New("token sudah dicabut")
+	}
+	return nil
+}
+
+// IssueStreamToken membuat token berumur pendek untuk membuka stream SSE.
+// Token hanya dipakai saat koneksi dibuka; client meminta token baru setiap
+// kali EventSource perlu tersambung ulang.
+func (s *AuthService) IssueStreamToken(userIDString string) (*dto.StreamTokenResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	user, err := s.userRepo.FindByID(uint(userID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	token, err := utils.GenerateStreamToken(user.ID, user.Role, user.TokenVersion)
+	if err != nil {
+		return nil, errors.New("gagal membuat stream token")
+	}
+
+	return &dto.StreamTokenResponse{
+		Token:     token,
+		ExpiresIn: int(utils.StreamTokenTTL().Seconds()),
+	}, nil
+}
+
+func (s *AuthService) revokeAllSessions(userID uint) error {
+	if err := s.refreshRepo.RevokeAllByUserID(userID); err != nil {
+		return errors.New("gagal mencabut sesi")
+	}
+	if err := s.userRepo.IncrementTokenVersion(userID); err != nil {
+		return errors.New("gagal mencabut sesi")
+	}
+	return nil
+}
+
+func (s *AuthService) issueTokens(user *model.User) (*dto.AuthResponse, error) {
+	response, _, err := s.issueTokensWithID(user)
+	return response, err
+}
+
+func (s *AuthService) issueTokensWithID(user *model.User) (*dto.AuthResponse, uint, error) {
+	accessToken, err := utils.GenerateToken(user.ID, user.Role, user.TokenVersion)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stored, err := s.refreshRepo.Create(&model.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &dto.AuthResponse{
+		ID:           user.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+		Role:         user.Role,
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL().Seconds()),
+	}, stored.ID, nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+)
+
+const fakeEmbeddingDimensions = 64
+
+var (
+	fakeQuestionCountPattern  = regexp.MustCompile(`(\d+) soal`)
+	fakeQuizMixPattern        = regexp.MustCompile(`(?m)^- (\d+) soal (\w+)`)
+	fakeFlashcardCountPattern = regexp.MustCompile(`(\d+) kartu flashcard`)
+)
+
+// FakeLLMProvider mengembalikan respons deterministik tanpa akses jaringan,
+// dipakai untuk menjalankan alur materi/rangkuman/kuis di CI. Isi TextResponse
+// atau JSONResponse untuk mengganti respons bawaan.
+type FakeLLMProvider struct {
+	TextResponse string
+	JSONResponse string
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+func NewFakeLLMProvider() *FakeLLMProvider {
+	return &FakeLLMProvider{}
+}
+
+func (p *FakeLLMProvider) GenerateText(ctx context.Context, prompt string) (string, error) {
+	p.record(prompt)
+	if p.TextResponse != "" {
+		return p.TextResponse, nil
+	}
+	return fmt.Sprintf("Rangkuman contoh dari fake LLM provider (panjang prompt: %d karakter).", len(prompt)), nil
+}
+
+// StreamText mengirim respons GenerateText kata demi kata.
+func (p *FakeLLMProvider) StreamText(ctx context.Context, prompt string, onChunk func(chunk string) error) (string, error) {
+	text, err := p.GenerateText(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	for _, word := range strings.SplitAfter(text, " ") {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if err := onChunk(word); err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+func (p *FakeLLMProvider) GenerateJSON(ctx context.Context, prompt string, schema *JSONSchema) (string, error) {
+	p.record(prompt)
+	if p.JSONResponse != "" {
+		return p.JSONResponse, nil
+	}
+
+	if match := fakeFlashcardCountPattern.FindStringSubmatch(prompt); match != nil {
+		count, _ := strconv.Atoi(match[1])
+		return fakeFlashcardJSON(count), nil
+	}
+
+	mix := map[string]int{}
+	for _, match := range fakeQuizMixPattern.FindAllStringSubmatch(prompt, -1) {
+		count, _ := strconv.Atoi(match[1])
+		mix[match[2]] += count
+	}
+	if len(mix) == 0 {
+		count := 1
+		if match := fakeQuestionCountPattern.FindStringSubmatch(prompt); match != nil {
+			count, _ = strconv.Atoi(match[1])
+		}
+		mix[model.QuizTypeMultipleChoice] = count
+	}
+	return fakeQuizJSON(mix), nil
+}
+
+// Embed menghasilkan vektor bag-of-words sederhana: setiap kata di-hash ke
+// salah satu dimensi, sehingga teks dengan kata yang mirip punya cosine
+// similarity tinggi tanpa perlu model sungguhan.
+func (p *FakeLLMProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vector := make([]float32, fakeEmbeddingDimensions)
+		for _, word := range strings.Fields(strings.ToLower(text)) {
+			hash := fnv.New32a()
+			hash.Write([]byte(strings.Trim(word, ".,;:!?()\"'")))
+			vector[hash.Sum32()%fakeEmbeddingDimensions]++
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+// Prompts mengembalikan semua prompt yang pernah diterima, berguna untuk
+// memeriksa isi prompt di test.
+func (p *FakeLLMProvider) Prompts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.prompts...)
+}
+
+func (p *FakeLLMProvider) record(prompt string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prompts = append(p.prompts, prompt)
+}
+
+func fakeQuizJSON(mix map[string]int) string {
+	letters := []string{"A", "B", "C", "D"}
+	options := []map[string]string{
+		{"A": "Pilihan A"},
+		{"B": "Pilihan B"},
+		{"C": "Pilihan C"},
+		{"D": "Pilihan D"},
+	}
+
+	var questions []map[string]interface{}
+	for _, questionType := range quizTypes {
+		for n := 0; n < mix[questionType]; n++ {
+			i := len(questions) + 1
+			question := map[string]interface{}{
+				"id":         i,
+				"tipe":       questionType,
+				"pertanyaan": fmt.Sprintf("Pertanyaan contoh nomor %d?", i),
+			}
+
+			switch questionType {
+			case model.QuizTypeMultipleChoice:
+				question["pilihan"] = options
+				question["jawaban_benar"] = letters[(i-1)%len(letters)]
+			case model.QuizTypeMultipleAnswer:
+				question["pilihan"] = options
+				question["jawaban_benar"] = "A,C"
+			case model.QuizTypeTrueFalse:
+				question["jawaban_benar"] = []string{"benar", "salah"}[i%2]
+			case model.QuizTypeShortAnswer:
+				question["jawaban_benar"] = fmt.Sprintf("jawaban %d", i)
+				question["jawaban_alternatif"] = []string{fmt.Sprintf("jawaban nomor %d", i)}
+			case model.QuizTypeFillBlank:
+				question["pertanyaan"] = fmt.Sprintf("Kalimat contoh nomor %d dengan bagian ___ yang kosong.", i)
+				question["jawaban_benar"] = "rumpang"
+			}
+			questions = append(questions, question)
+		}
+	}
+
+	data, _ := json.Marshal(questions)
+	return string(data)
+}
+
+func fakeFlashcardJSON(count int) string {
+	cards := make([]map[string]string, 0, count)
+	for i := 1; i <= count; i++ {
+		cards = append(cards, map[string]string{
+			"depan":    fmt.Sprintf("Istilah contoh nomor %d", i),
+			"belakang": fmt.Sprintf("Penjelasan contoh nomor %d.", i),
+		})
+	}
+
+	data, _ := json.Marshal(cards)
+	return string(data)
+}
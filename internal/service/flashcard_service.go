@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+
+	defaultDueFlashcardLimit = 20
+	maxDueFlashcardLimit     = 100
+)
+
+type FlashcardService struct {
+	flashcardRepo *repository.FlashcardRepository
+	matRepo       *repository.MaterialRepository
+	userRepo      *repository.UserRepository
+	ai            *AIService
+}
+
+func NewFlashcardService(flashcardRepo *repository.FlashcardRepository, matRepo *repository.MaterialRepository, userRepo *repository.UserRepository, ai *AIService) *FlashcardService {
+	return &FlashcardService{flashcardRepo: flashcardRepo, matRepo: matRepo, userRepo: userRepo, ai: ai}
+}
+
+// GenerateDeck membuat deck flashcard dari materi. Semua kartu baru langsung
+// jatuh tempo hari ini.
+func (s *FlashcardService) GenerateDeck(ctx context.Context, req dto.GenerateFlashcardsRequest, userIDString string) (*dto.FlashcardDeckResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	material, err := s.matRepo.FindByID(req.MaterialID, uint(userID))
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan atau anda tidak punya akses")
+	}
+
+	today, err := s.userToday(uint(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	items, chunksCovered, totalChunks, err := s.ai.GenerateFlashcardItems(ctx, material, req.CardCount)
+	if err != nil {
+		return nil, err
+	}
+
+	deck := &model.FlashcardDeck{
+		UserID:     uint(userID),
+		MaterialID: material.ID,
+		Title:      material.Title,
+	}
+	for _, item := range items {
+		deck.Cards = append(deck.Cards, model.Flashcard{
+			UserID:     uint(userID),
+			Front:      item.Depan,
+			Back:       item.Belakang,
+			EaseFactor: defaultEaseFactor,
+			DueDate:    today,
+		})
+	}
+
+	savedDeck, err := s.flashcardRepo.CreateDeck(deck)
+	if err != nil {
+		return nil, errors.New("gagal menyimpan flashcard")
+	}
+
+	log.Printf("[Flashcard] User %d membuat deck %d (%d kartu) dari materi %d.", userID, savedDeck.ID, len(savedDeck.Cards), material.ID)
+
+	response := &dto.FlashcardDeckResponse{
+		ID:            savedDeck.ID,
+		MaterialID:    savedDeck.MaterialID,
+		Title:         savedDeck.Title,
+		ChunksCovered: chunksCovered,
+		TotalChunks:   totalChunks,
+		Cards:         []dto.FlashcardResponse{},
+		CreatedAt:     savedDeck.CreatedAt,
+	}
+	for _, card := range savedDeck.Cards {
+		response.Cards = append(response.Cards, flashcardToResponse(&card))
+	}
+	return response, nil
+}
+
+func (s *FlashcardService) GetDueCards(userIDString string, limitQuery string) (*dto.DueFlashcardsResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	limit := defaultDueFlashcardLimit
+	if limitQuery != "" {
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil || limit < 1 {
+			return nil, errors.New("parameter limit tidak valid")
+		}
+		limit = min(limit, maxDueFlashcardLimit)
+	}
+
+	today, err := s.userToday(uint(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	cards, total, err := s.flashcardRepo.FindDue(uint(userID), today, limit)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data flashcard")
+	}
+
+	response := &dto.DueFlashcardsResponse{
+		Date:     today.Format("2006-01-02"),
+		DueCount: total,
+		Cards:    []dto.FlashcardResponse{},
+	}
+	for _, card := range cards {
+		response.Cards = append(response.Cards, flashcardToResponse(&card))
+	}
+	return response, nil
+}
+
+func (s *FlashcardService) ReviewCard(cardIDString string, userIDString string, req dto.ReviewFlashcardRequest) (*dto.FlashcardResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	cardID, err := strconv.ParseUint(cardIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("flashcard ID tidak valid")
+	}
+
+	card, err := s.flashcardRepo.FindByID(uint(cardID), uint(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("flashcard tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil data flashcard")
+	}
+
+	today, err := s.userToday(uint(userID))
+	if err != nil {
+		return nil, err
+	}
+	// Review sebelum jatuh tempo ditolak supaya interval tidak bisa
+	// dinaikkan dengan mengulang kartu yang sama berkali-kali.
+	if civilDate(card.DueDate).After(today) {
+		return nil, errors.New("flashcard belum jatuh tempo")
+	}
+
+	scheduleSM2(card, *req.Grade, today)
+	now := time.Now()
+	card.LastReviewedAt = &now
+
+	updatedCard, err := s.flashcardRepo.Update(card)
+	if err != nil {
+		return nil, errors.New("gagal menyimpan review flashcard")
+	}
+
+	response := flashcardToResponse(updatedCard)
+	return &response, nil
+}
+
+func (s *FlashcardService) userToday(userID uint) (time.Time, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return time.Time{}, errors.New("user tidak ditemukan")
+	}
+	return userToday(user, time.Now()), nil
+}
+
+// scheduleSM2 menerapkan algoritma SuperMemo-2. Grade di bawah 3 dianggap
+// lupa: repetisi diulang dari awal dan kartu muncul lagi besok. Ease factor
+// tetap disesuaikan untuk semua grade dengan batas bawah 1.3.
+func scheduleSM2(card *model.Flashcard, grade int, today time.Time) {
+	if card.EaseFactor == 0 {
+		card.EaseFactor = defaultEaseFactor
+	}
+
+	if grade < 3 {
+		card.Repetitions = 0
+		card.IntervalDays = 1
+	} else {
+		switch card.Repetitions {
+		case 0:
+			card.IntervalDays = 1
+		case 1:
+			card.IntervalDays = 6
+		default:
+			card.IntervalDays = int(math.Round(float64(card.IntervalDays) * card.EaseFactor))
+		}
+		card.Repetitions++
+	}
+
+	q := float64(5 - grade)
+	card.EaseFactor = math.Max(minEaseFactor, card.EaseFactor+0.1-q*(0.08+q*0.02))
+	card.DueDate = civilDate(today).AddDate(0, 0, card.IntervalDays)
+}
+
+func flashcardToResponse(card *model.Flashcard) dto.FlashcardResponse {
+	return dto.FlashcardResponse{
+		ID:             card.ID,
+		DeckID:         card.DeckID,
+		Front:          card.Front,
+		Back:           card.Back,
+		EaseFactor:     card.EaseFactor,
+		IntervalDays:   card.IntervalDays,
+		Repetitions:    card.Repetitions,
+		DueDate:        civilDate(card.DueDate).Format("2006-01-02"),
+		LastReviewedAt: card.LastReviewedAt,
+	}
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"gorm.io/gorm"
+)
+
+type FocusService struct {
+	focusRepo    *repository.FocusSessionRepository
+	taskRepo     *repository.TaskRepository
+	userRepo     *repository.UserRepository
+	achievements *AchievementService
+}
+
+func NewFocusService(focusRepo *repository.FocusSessionRepository, taskRepo *repository.TaskRepository, userRepo *repository.UserRepository, achievements *AchievementService) *FocusService {
+	return &FocusService{focusRepo: focusRepo, taskRepo: taskRepo, userRepo: userRepo, achievements: achievements}
+}
+
+func (s *FocusService) StartSession(userIDString string, req dto.StartFocusRequest) (*dto.FocusSessionResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	_, err = s.focusRepo.FindActiveByUserID(uint(userID))
+	if err == nil {
+		return nil, errors.New("masih ada sesi fokus yang aktif")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("gagal mengambil data sesi fokus")
+	}
+
+	if req.TaskID != nil {
+		task, err := s.taskRepo.FindByID(*req.TaskID)
+		if err != nil || task.UserID != uint(userID) {
+			return nil, errors.New("task tidak ditemukan")
+		}
+	}
+
+	session := &model.FocusSession{
+		UserID:         uint(userID),
+		TaskID:         req.TaskID,
+		Status:         model.FocusSessionRunning,
+		PlannedMinutes: req.PlannedMinutes,
+		StartedAt:      time.Now(),
+	}
+	savedSession, err := s.focusRepo.Create(session)
+	if err != nil {
+		return nil, errors.New("gagal memulai sesi fokus")
+	}
+
+	log.Printf("[Focus] User %d memulai sesi %d (%d menit).", userID, savedSession.ID, savedSession.PlannedMinutes)
+	response := focusSessionToResponse(savedSession, time.Now())
+	return &response, nil
+}
+
+func (s *FocusService) PauseSession(sessionIDString string, userIDString string) (*dto.FocusSessionResponse, error) {
+	session, err := s.findOwnedSession(sessionIDString, userIDString)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != model.FocusSessionRunning {
+		return nil, errors.New("sesi fokus tidak sedang berjalan")
+	}
+
+	now := time.Now()
+	session.Status = model.FocusSessionPaused
+	session.PausedAt = &now
+
+	return s.saveSession(session, now)
+}
+
+func (s *FocusService) ResumeSession(sessionIDString string, userIDString string) (*dto.FocusSessionResponse, error) {
+	session, err := s.findOwnedSession(sessionIDString, userIDString)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != model.FocusSessionPaused {
+		return nil, errors.New("sesi fokus tidak sedang dijeda")
+	}
+
+	now := time.Now()
+	session.PausedSeconds += int(now.Sub(*session.PausedAt).Seconds())
+	session.Status = model.FocusSessionRunning
+	session.PausedAt = nil
+
+	return s.saveSession(session, now)
+}
+
+func (s *FocusService) StopSession(sessionIDString string, userIDString string) (*dto.FocusSessionResponse, error) {
+	session, err := s.findOwnedSession(sessionIDString, userIDString)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == model.FocusSessionEnded {
+		return nil, errors.New("sesi fokus sudah selesai")
+	}
+
+	now := time.Now()
+	session.ActualSeconds = focusedSeconds(session, now)
+	if session.PausedAt != nil {
+		session.PausedSeconds += int(now.Sub(*session.PausedAt).Seconds())
+		session.PausedAt = nil
+	}
+	session.Status = model.FocusSessionEnded
+	session.EndedAt = &now
+
+	log.Printf("[Focus] User %d menyelesaikan sesi %d, fokus %d detik.", session.UserID, session.ID, session.ActualSeconds)
+	response, err := s.saveSession(session, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.achievements.Publish(session.UserID, AchievementEventFocusEnded)
+	return response, nil
+}
+
+func (s *FocusService) GetActiveSession(userIDString string) (*dto.FocusSessionResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	session, err := s.focusRepo.FindActiveByUserID(uint(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.New("gagal mengambil data sesi fokus")
+	}
+
+	response := focusSessionToResponse(session, time.Now())
+	return &response, nil
+}
+
+func (s *FocusService) GetSessions(userIDString string, fromQuery string, toQuery string) ([]dto.FocusSessionResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	loc, err := s.userLocation(uint(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	localNow := now.In(loc)
+	today := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, loc)
+	from := today.AddDate(0, 0, -6)
+	to := today
+
+	if fromQuery != "" {
+		from, err = time.ParseInLocation("2006-01-02", fromQuery, loc)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+		}
+	}
+	if toQuery != "" {
+		to, err = time.ParseInLocation("2006-01-02", toQuery, loc)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+		}
+	}
+	if to.Before(from) {
+		return nil, errors.New("tanggal akhir harus setelah tanggal awal")
+	}
+
+	sessions, err := s.focusRepo.FindByUserIDBetween(uint(userID), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, errors.New("gagal mengambil data sesi fokus")
+	}
+
+	responses := []dto.FocusSessionResponse{}
+	for _, session := range sessions {
+		responses = append(responses, focusSessionToResponse(&session, now))
+	}
+	return responses, nil
+}
+
+func (s *FocusService) GetStats(userIDString string, period string, dateQuery string) (*dto.FocusStatsResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	loc, err := s.userLocation(uint(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	localNow := now.In(loc)
+	date := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, loc)
+	if dateQuery != "" {
+		date, err = time.ParseInLocation("2006-01-02", dateQuery, loc)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+		}
+	}
+
+	var start, end time.Time
+	switch period {
+	case "", "daily":
+		period = "daily"
+		start = date
+		end = start.AddDate(0, 0, 1)
+	case "weekly":
+		offset := (int(date.Weekday()) + 6) % 7
+		start = date.AddDate(0, 0, -offset)
+		end = start.AddDate(0, 0, 7)
+	case "monthly":
+		start = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		end = start.AddDate(0, 1, 0)
+	default:
+		return nil, errors.New("period harus daily, weekly, atau monthly")
+	}
+
+	sessions, err := s.focusRepo.FindByUserIDBetween(uint(userID), start, end)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data sesi fokus")
+	}
+
+	response := &dto.FocusStatsResponse{
+		Period:    period,
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+
+	dayIndex := map[string]int{}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		dayIndex[key] = len(response.Days)
+		response.Days = append(response.Days, dto.FocusDayStat{Date: key})
+	}
+
+	for _, session := range sessions {
+		minutes := focusedSeconds(&session, now) / 60
+		key := session.StartedAt.In(start.Location()).Format("2006-01-02")
+
+		response.TotalMinutes += minutes
+		response.PlannedMinutes += session.PlannedMinutes
+		response.SessionCount++
+		if i, ok := dayIndex[key]; ok {
+			response.Days[i].Minutes += minutes
+			response.Days[i].Sessions++
+		}
+	}
+
+	return response, nil
+}
+
+// userLocation mengembalikan zona waktu user supaya batas hari pada statistik
+// sama dengan batas hari pada streak.
+func (s *FocusService) userLocation(userID uint) (*time.Location, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	return userLocation(user), nil
+}
+
+func (s *FocusService) findOwnedSession(sessionIDString string, userIDString string) (*model.FocusSession, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	sessionID, err := strconv.ParseUint(sessionIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("session ID tidak valid")
+	}
+
+	session, err := s.focusRepo.FindByID(uint(sessionID), uint(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("sesi fokus tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil data sesi fokus")
+	}
+	return session, nil
+}
+
+func (s *FocusService) saveSession(session *model.FocusSession, now time.Time) (*dto.FocusSessionResponse, error) {
+	updatedSession, err := s.focusRepo.Update(session)
+	if err != nil {
+		return nil, errors.New("gagal menyimpan sesi fokus")
+	}
+
+	response := focusSessionToResponse(updatedSession, now)
+	return &response, nil
+}
+
+// focusedSeconds menghitung durasi fokus bersih (tanpa jeda). Untuk sesi yang
+// masih berjalan, durasi dihitung sampai now.
+func focusedSeconds(session *model.FocusSession, now time.Time) int {
+	if session.Status == model.FocusSessionEnded {
+		return session.ActualSeconds
+	}
+
+	until := now
+	if session.PausedAt != nil {
+		until = *session.PausedAt
+	}
+
+	seconds := int(until.Sub(session.StartedAt).Seconds()) - session.PausedSeconds
+	if seconds < 0 {
+		return 0
+	}
+	return seconds
+}
+
+func focusSessionToResponse(session *model.FocusSession, now time.Time) dto.FocusSessionResponse {
+	return dto.FocusSessionResponse{
+		ID:             session.ID,
+		TaskID:         session.TaskID,
+		Status:         session.Status,
+		PlannedMinutes: session.PlannedMinutes,
+		ActualMinutes:  focusedSeconds(session, now) / 60,
+		StartedAt:      session.StartedAt,
+		PausedAt:       session.PausedAt,
+		EndedAt:        session.EndedAt,
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+const (
+	defaultGeminiModel          = "gemini-2.5-flash"
+	defaultGeminiEmbeddingModel = "text-embedding-004"
+
+	// geminiEmbedBatchSize adalah batas jumlah teks per BatchEmbedContents.
+	geminiEmbedBatchSize = 100
+)
+
+type GeminiProvider struct {
+	textModel  *genai.GenerativeModel
+	jsonModel  *genai.GenerativeModel
+	embedModel *genai.EmbeddingModel
+}
+
+func NewGeminiProvider(apiKey string, modelName string, embeddingModelName string) (*GeminiProvider, error) {
+	if apiKey == "" {
+		return nil, errors.New("GEMINI_API_KEY tidak ditemukan")
+	}
+	if modelName == "" {
+		modelName = defaultGeminiModel
+	}
+	if embeddingModelName == "" {
+		embeddingModelName = defaultGeminiEmbeddingModel
+	}
+
+	ctx := context.Background()
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, err
+	}
+
+	jsonModel := client.GenerativeModel(modelName)
+	jsonModel.ResponseMIMEType = "application/json"
+
+	return &GeminiProvider{
+		textModel:  client.GenerativeModel(modelName),
+		jsonModel:  jsonModel,
+		embedModel: client.EmbeddingModel(embeddingModelName),
+	}, nil
+}
+
+func (p *GeminiProvider) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return p.generate(ctx, p.textModel, prompt)
+}
+
+func (p *GeminiProvider) StreamText(ctx context.Context, prompt string, onChunk func(chunk string) error) (string, error) {
+	iter := p.textModel.GenerateContentStream(ctx, genai.Text(prompt))
+
+	var result strings.Builder
+	for {
+		resp, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("gagal memanggil Gemini: %w", err)
+		}
+
+		chunk := responseText(resp)
+		if chunk == "" {
+			continue
+		}
+		result.WriteString(chunk)
+		if err := onChunk(chunk); err != nil {
+			return "", err
+		}
+	}
+	return result.String(), nil
+}
+
+func (p *GeminiProvider) GenerateJSON(ctx context.Context, prompt string, schema *JSONSchema) (string, error) {
+	if schema == nil {
+		return p.generate(ctx, p.jsonModel, prompt)
+	}
+
+	model := *p.jsonModel
+	model.ResponseSchema = geminiSchema(schema)
+	return p.generate(ctx, &model, prompt)
+}
+
+func (p *GeminiProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += geminiEmbedBatchSize {
+		end := min(start+geminiEmbedBatchSize, len(texts))
+
+		batch := p.embedModel.NewBatch()
+		for _, text := range texts[start:end] {
+			batch.AddContent(genai.Text(text))
+		}
+
+		resp, err := p.embedModel.BatchEmbedContents(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memanggil embedding Gemini: %w", err)
+		}
+		if len(resp.Embeddings) != end-start {
+			return nil, fmt.Errorf("embedding Gemini mengembalikan %d vektor untuk %d teks", len(resp.Embeddings), end-start)
+		}
+		for _, embedding := range resp.Embeddings {
+			vectors = append(vectors, embedding.Values)
+		}
+	}
+	return vectors, nil
+}
+
+func (p *GeminiProvider) generate(ctx context.Context, model *genai.GenerativeModel, prompt string) (string, error) {
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return "", fmt.Errorf("gagal memanggil Gemini: %w", err)
+	}
+
+	return responseText(resp), nil
+}
+
+func responseText(resp *genai.GenerateContentResponse) string {
+	var result string
+	if len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
+		for _, part := range resp.Candidates[0].Content.Parts {
+			if txt, ok := part.(genai.Text); ok {
+				result += string(txt)
+			}
+		}
+	}
+	return result
+}
+
+func geminiSchema(schema *JSONSchema) *genai.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	types := map[string]genai.Type{
+		"object":  genai.TypeObject,
+		"array":   genai.TypeArray,
+		"string":  genai.TypeString,
+		"integer": genai.TypeInteger,
+		"number":  genai.TypeNumber,
+		"boolean": genai.TypeBoolean,
+	}
+
+	result := &genai.Schema{
+		Type:     types[schema.Type],
+		Items:    geminiSchema(schema.Items),
+		Required: schema.Required,
+		Enum:     schema.Enum,
+	}
+	if len(schema.Properties) > 0 {
+		result.Properties = make(map[string]*genai.Schema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			result.Properties[name] = geminiSchema(property)
+		}
+	}
+	return result
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"strconv"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	ingestionPollInterval  = 5 * time.Second
+	ingestionStaleAfter    = 20 * time.Minute
+	ingestionMaxAttempts   = 3
+	ingestionJobTimeout    = 15 * time.Minute
+	ingestionHeartbeat     = time.Minute
+	defaultIngestionWorker = 2
+)
+
+type IngestionService struct {
+	jobRepo   *repository.IngestionJobRepository
+	aiService *AIService
+	wake      chan struct{}
+}
+
+func NewIngestionService(jobRepo *repository.IngestionJobRepository, aiService *AIService) *IngestionService {
+	return &IngestionService{jobRepo: jobRepo, aiService: aiService}
+}
+
+func (s *IngestionService) EnqueuePDF(fileHeader *multipart.FileHeader, title string, userIDString string) (*dto.IngestionJobResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, errors.New("gagal membuka file")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.New("gagal membaca file")
+	}
+
+	return s.enqueue(&model.IngestionJob{
+		UserID:     uint(userID),
+		SourceType: "pdf",
+		Title:      title,
+		Source:     fileHeader.Filename,
+		FileData:   data,
+	})
+}
+
+func (s *IngestionService) EnqueueYouTube(req dto.IngestYouTubeRequest, userIDString string) (*dto.IngestionJobResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	if !utils.YouTubeEnabled() {
+		return nil, errors.New("ingest YouTube belum dikonfigurasi")
+	}
+
+	return s.enqueue(&model.IngestionJob{
+		UserID:     uint(userID),
+		SourceType: "youtube",
+		Title:      req.Title,
+		Source:     req.URL,
+	})
+}
+
+func (s *IngestionService) GetJob(jobIDString string, userIDString string) (*dto.IngestionJobResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	jobID, err := strconv.ParseUint(jobIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("job ID tidak valid")
+	}
+
+	job, err := s.jobRepo.FindByID(uint(jobID), uint(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("job tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil data job")
+	}
+
+	response := ingestionJobToResponse(job)
+	return &response, nil
+}
+
+// StartWorkers menjalankan worker ingestion di dalam proses API. Job running
+// yang tidak bergerak lebih dari ingestionStaleAfter dikembalikan ke antrean,
+// baik saat start maupun berkala. Worker yang masih hidup memperbarui
+// updated_at setiap ingestionHeartbeat, jadi hanya job dari proses yang mati
+// yang diambil ulang.
+func (s *IngestionService) StartWorkers(ctx context.Context, concurrency int) {
+	if concurrency <= 0 {
+		concurrency = defaultIngestionWorker
+	}
+	s.wake = make(chan struct{}, concurrency)
+
+	s.recoverStaleJobs(time.Now().Add(-ingestionStaleAfter))
+
+	go func() {
+		ticker := time.NewTicker(ingestionStaleAfter / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.recoverStaleJobs(time.Now().Add(-ingestionStaleAfter))
+			}
+		}
+	}()
+
+	for i := 0; i < concurrency; i++ {
+		go s.runWorker(ctx, i+1)
+	}
+	log.Printf("[Ingestion] %d worker berjalan.", concurrency)
+}
+
+func (s *IngestionService) enqueue(job *model.IngestionJob) (*dto.IngestionJobResponse, error) {
+	job.Status = model.IngestionJobQueued
+
+	savedJob, err := s.jobRepo.Create(job)
+	if err != nil {
+		return nil, errors.New("gagal membuat job ingestion")
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	log.Printf("[Ingestion] Job %d (%s) masuk antrean.", savedJob.ID, savedJob.SourceType)
+	response := ingestionJobToResponse(savedJob)
+	return &response, nil
+}
+
+func (s *IngestionService) recoverStaleJobs(staleBefore time.Time) {
+	requeued, err := s.jobRepo.RequeueStale(staleBefore, ingestionMaxAttempts)
+	if err != nil {
+		log.Printf("[Ingestion] Gagal memulihkan job yang terhenti: %v", err)
+		return
+	}
+	if requeued > 0 {
+		log.Printf("[Ingestion] %d job yang terhenti dikembalikan ke antrean.", requeued)
+	}
+}
+
+func (s *IngestionService) runWorker(ctx context.Context, workerID int) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		job, err := s.jobRepo.ClaimNext()
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("[Ingestion] Worker %d gagal mengambil job: %v", workerID, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+			case <-time.After(ingestionPollInterval):
+			}
+			continue
+		}
+
+		s.processJob(ctx, workerID, job)
+	}
+}
+
+func (s *IngestionService) processJob(ctx context.Context, workerID int, job *model.IngestionJob) {
+	log.Printf("[Ingestion] Worker %d memproses job %d (percobaan %d).", workerID, job.ID, job.Attempts)
+
+	jobCtx, cancel := context.WithTimeout(ctx, ingestionJobTimeout)
+	defer cancel()
+	go s.heartbeat(jobCtx, cancel, job)
+
+	if err := s.jobRepo.UpdateProgress(job.ID, 10); err != nil {
+		log.Printf("[Ingestion] Gagal update progress job %d: %v", job.ID, err)
+	}
+
+	var material *model.Material
+	var err error
+	switch job.SourceType {
+	case "pdf":
+		material, err = s.aiService.IngestPDF(jobCtx, job.FileData, job.Source, job.Title, job.UserID)
+	case "youtube":
+		material, err = s.aiService.IngestYouTube(jobCtx, job.Source, job.Title, job.UserID)
+	default:
+		err = fmt.Errorf("source type %q tidak dikenal", job.SourceType)
+	}
+
+	if err != nil {
+		log.Printf("[Ingestion] Job %d gagal: %v", job.ID, err)
+		marked, markErr := s.jobRepo.MarkFailed(job.ID, job.Attempts, err.Error())
+		if markErr != nil {
+			log.Printf("[Ingestion] Gagal menandai job %d gagal: %v", job.ID, markErr)
+		} else if !marked {
+			log.Printf("[Ingestion] Job %d sudah diambil alih percobaan lain, hasil gagal diabaikan.", job.ID)
+		}
+		return
+	}
+
+	marked, err := s.jobRepo.MarkSucceeded(job.ID, job.Attempts, material.ID)
+	if err != nil {
+		log.Printf("[Ingestion] Gagal menandai job %d sukses: %v", job.ID, err)
+		return
+	}
+	if !marked {
+		log.Printf("[Ingestion] Job %d sudah diambil alih percobaan lain, materi %d tetap tersimpan.", job.ID, material.ID)
+		return
+	}
+	log.Printf("[Ingestion] Job %d selesai, materi ID: %d", job.ID, material.ID)
+}
+
+// heartbeat menjaga updated_at job tetap baru selama jobCtx berjalan. Jika
+// job ternyata sudah diambil alih (misalnya setelah koneksi database putus
+// cukup lama), pekerjaan dibatalkan lewat cancel.
+func (s *IngestionService) heartbeat(jobCtx context.Context, cancel context.CancelFunc, job *model.IngestionJob) {
+	ticker := time.NewTicker(ingestionHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-jobCtx.Done():
+			return
+		case <-ticker.C:
+			alive, err := s.jobRepo.Touch(job.ID, job.Attempts)
+			if err != nil {
+				log.Printf("[Ingestion] Gagal memperbarui heartbeat job %d: %v", job.ID, err)
+				continue
+			}
+			if !alive {
+				log.Printf("[Ingestion] Job %d sudah diambil alih, percobaan %d dibatalkan.", job.ID, job.Attempts)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func ingestionJobToResponse(job *model.IngestionJob) dto.IngestionJobResponse {
+	return dto.IngestionJobResponse{
+		ID:         job.ID,
+		SourceType: job.SourceType,
+		Title:      job.Title,
+		Source:     job.Source,
+		Status:     job.Status,
+		Progress:   job.Progress,
+		Error:      job.Error,
+		MaterialID: job.MaterialID,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mohamadarif03/focus-room-be/internal/config"
+)
+
+// LLMProvider adalah abstraksi backend model bahasa yang dipakai AIService.
+// GenerateJSON dipakai untuk prompt yang hasilnya akan di-parse sebagai JSON;
+// schema opsional dan hanya dipakai provider yang mendukung response schema.
+// StreamText sama dengan GenerateText, tetapi memanggil onChunk untuk setiap
+// potongan teks begitu diterima; error dari onChunk menghentikan stream.
+// Embed mengembalikan satu vektor untuk setiap teks, dengan urutan yang sama.
+type LLMProvider interface {
+	GenerateText(ctx context.Context, prompt string) (string, error)
+	StreamText(ctx context.Context, prompt string, onChunk func(chunk string) error) (string, error)
+	GenerateJSON(ctx context.Context, prompt string, schema *JSONSchema) (string, error)
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// JSONSchema adalah subset JSON Schema yang cukup untuk mendeskripsikan
+// output terstruktur seperti kuis. Type berisi "object", "array", "string",
+// "integer", "number", atau "boolean".
+type JSONSchema struct {
+	Type       string
+	Properties map[string]*JSONSchema
+	Items      *JSONSchema
+	Required   []string
+	Enum       []string
+}
+
+func NewLLMProvider(cfg config.LLMConfig) (LLMProvider, error) {
+	switch cfg.Provider {
+	case "", "gemini":
+		return NewGeminiProvider(cfg.APIKey, cfg.Model, cfg.EmbeddingModel)
+	case "openai":
+		return NewOpenAIProvider(cfg.BaseURL, cfg.APIKey, cfg.Model, cfg.EmbeddingModel)
+	case "fake":
+		return NewFakeLLMProvider(), nil
+	default:
+		return nil, fmt.Errorf("LLM provider %q tidak dikenal", cfg.Provider)
+	}
+}
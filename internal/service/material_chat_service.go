@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	chatTopK             = 4
+	chatHistoryMessages  = 6
+	maxChatHistoryLength = 200
+	citationSnippetRunes = 200
+)
+
+const materialChatPrompt = `Kamu adalah asisten belajar yang menjawab pertanyaan siswa berdasarkan materi "%s".
+
+Gunakan HANYA kutipan materi di bawah ini. Jika jawabannya tidak ada di kutipan, katakan dengan jujur bahwa materi tidak membahasnya. Setiap klaim harus diberi sitasi nomor kutipan dalam kurung siku, misalnya [1] atau [2][3]. Jawab dalam bahasa Indonesia yang jelas dan ringkas.
+
+Kutipan materi:
+%s
+Riwayat percakapan:
+%s
+Pertanyaan siswa:
+%s`
+
+var citationRefPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+type scoredPassage struct {
+	passage model.MaterialPassage
+	score   float64
+}
+
+// MaterialChatService menjawab pertanyaan tentang satu materi dengan
+// retrieval-augmented generation: passage materi di-embed, dipilih top-k
+// berdasarkan cosine similarity, lalu dikirim ke LLM beserta riwayat chat.
+type MaterialChatService struct {
+	llm      LLMProvider
+	matRepo  *repository.MaterialRepository
+	chatRepo *repository.MaterialChatRepository
+}
+
+func NewMaterialChatService(llm LLMProvider, matRepo *repository.MaterialRepository, chatRepo *repository.MaterialChatRepository) *MaterialChatService {
+	return &MaterialChatService{llm: llm, matRepo: matRepo, chatRepo: chatRepo}
+}
+
+func (s *MaterialChatService) Ask(ctx context.Context, materialIDString string, userIDString string, req dto.MaterialChatRequest) (*dto.MaterialChatResponse, error) {
+	material, err := s.findMaterial(materialIDString, userIDString)
+	if err != nil {
+		return nil, err
+	}
+
+	passages, err := s.ensureEmbeddedPassages(ctx, material)
+	if err != nil {
+		return nil, err
+	}
+
+	queryVectors, err := s.llm.Embed(ctx, []string{req.Question})
+	if err != nil || len(queryVectors) != 1 {
+		return nil, fmt.Errorf("gagal membuat embedding pertanyaan: %w", err)
+	}
+	top := topPassages(passages, queryVectors[0], chatTopK)
+
+	conversation, err := s.chatRepo.FindOrCreateConversation(material.UserID, material.ID)
+	if err != nil {
+		return nil, errors.New("gagal mengambil percakapan")
+	}
+	history, err := s.chatRepo.FindRecentMessages(conversation.ID, chatHistoryMessages)
+	if err != nil {
+		return nil, errors.New("gagal mengambil percakapan")
+	}
+
+	answer, err := s.llm.GenerateText(ctx, buildChatPrompt(material.Title, top, history, req.Question))
+	if err != nil {
+		return nil, err
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return nil, errors.New("LLM tidak memberikan jawaban")
+	}
+
+	messages := []model.MaterialChatMessage{
+		{ConversationID: conversation.ID, Role: model.ChatRoleUser, Content: req.Question},
+		{ConversationID: conversation.ID, Role: model.ChatRoleAssistant, Content: answer, Citations: citationsFor(answer, top)},
+	}
+	if err := s.chatRepo.CreateMessages(messages); err != nil {
+		return nil, errors.New("gagal menyimpan percakapan")
+	}
+
+	log.Printf("[Chat] User %d bertanya tentang materi %d (%d passage dipakai).", material.UserID, material.ID, len(top))
+	return &dto.MaterialChatResponse{
+		ConversationID: conversation.ID,
+		Question:       chatMessageToResponse(&messages[0]),
+		Answer:         chatMessageToResponse(&messages[1]),
+	}, nil
+}
+
+func (s *MaterialChatService) GetHistory(materialIDString string, userIDString string) (*dto.MaterialConversationResponse, error) {
+	material, err := s.findMaterial(materialIDString, userIDString)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.MaterialConversationResponse{MaterialID: material.ID, Messages: []dto.ChatMessageResponse{}}
+
+	conversation, err := s.chatRepo.FindConversation(material.UserID, material.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response, nil
+		}
+		return nil, errors.New("gagal mengambil percakapan")
+	}
+
+	messages, err := s.chatRepo.FindRecentMessages(conversation.ID, maxChatHistoryLength)
+	if err != nil {
+		return nil, errors.New("gagal mengambil percakapan")
+	}
+
+	response.ConversationID = conversation.ID
+	for _, message := range messages {
+		response.Messages = append(response.Messages, chatMessageToResponse(&message))
+	}
+	return response, nil
+}
+
+func (s *MaterialChatService) ClearHistory(materialIDString string, userIDString string) error {
+	material, err := s.findMaterial(materialIDString, userIDString)
+	if err != nil {
+		return err
+	}
+
+	if err := s.chatRepo.DeleteConversation(material.UserID, material.ID); err != nil {
+		return errors.New("gagal menghapus percakapan")
+	}
+	return nil
+}
+
+func (s *MaterialChatService) findMaterial(materialIDString string, userIDString string) (*model.Material, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	materialID, err := strconv.ParseUint(materialIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("material ID tidak valid")
+	}
+
+	material, err := s.matRepo.FindByID(uint(materialID), uint(userID))
+	if err != nil {
+		return nil, errors.New("materi tidak ditemukan atau anda tidak punya akses")
+	}
+	return material, nil
+}
+
+// ensureEmbeddedPassages menyiapkan passage dan embedding-nya. Materi lama
+// yang di-ingest sebelum chat ada dipecah dari ExtractedText tanpa lokasi.
+func (s *MaterialChatService) ensureEmbeddedPassages(ctx context.Context, material *model.Material) ([]model.MaterialPassage, error) {
+	passages, err := s.matRepo.FindPassagesByMaterialID(material.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil passage materi: %w", err)
+	}
+
+	if len(passages) == 0 {
+		passages = buildPassages(material.ID, []passageSource{{Text: material.ExtractedText}})
+		if len(passages) == 0 {
+			return nil, errors.New("materi tidak memiliki teks untuk diproses")
+		}
+		if err := s.matRepo.SavePassages(passages); err != nil {
+			return nil, fmt.Errorf("gagal menyimpan passage materi: %w", err)
+		}
+		// Baca ulang: request lain mungkin sudah menyimpan passage lebih dulu,
+		// dan passage yang dilewati OnConflict tidak punya ID.
+		passages, err = s.matRepo.FindPassagesByMaterialID(material.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil passage materi: %w", err)
+		}
+	}
+
+	var missing []int
+	var texts []string
+	for i, passage := range passages {
+		if len(passage.Embedding) == 0 {
+			missing = append(missing, i)
+			texts = append(texts, passage.Content)
+		}
+	}
+	if len(missing) == 0 {
+		return passages, nil
+	}
+
+	log.Printf("[Chat] Membuat embedding untuk %d passage materi %d...", len(missing), material.ID)
+	vectors, err := s.llm.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat embedding materi: %w", err)
+	}
+	if len(vectors) != len(missing) {
+		return nil, errors.New("jumlah embedding tidak sesuai dengan jumlah passage")
+	}
+
+	updated := make([]model.MaterialPassage, 0, len(missing))
+	for i, index := range missing {
+		passages[index].Embedding = vectors[i]
+		updated = append(updated, passages[index])
+	}
+	if err := s.matRepo.UpdatePassageEmbeddings(updated); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan embedding materi: %w", err)
+	}
+	return passages, nil
+}
+
+// topPassages memilih k passage dengan cosine similarity tertinggi.
+func topPassages(passages []model.MaterialPassage, query []float32, k int) []scoredPassage {
+	scored := make([]scoredPassage, 0, len(passages))
+	for _, passage := range passages {
+		scored = append(scored, scoredPassage{passage: passage, score: cosineSimilarity(passage.Embedding, query)})
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+	return scored[:min(k, len(scored))]
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func buildChatPrompt(title string, top []scoredPassage, history []model.MaterialChatMessage, question string) string {
+	var excerpts strings.Builder
+	for i, item := range top {
+		fmt.Fprintf(&excerpts, "[%d] (%s)\n%s\n\n", i+1, passageLocation(&item.passage), item.passage.Content)
+	}
+
+	var conversation strings.Builder
+	if len(history) == 0 {
+		conversation.WriteString("(belum ada)\n")
+	}
+	for _, message := range history {
+		speaker := "Siswa"
+		if message.Role == model.ChatRoleAssistant {
+			speaker = "Asisten"
+		}
+		fmt.Fprintf(&conversation, "%s: %s\n", speaker, message.Content)
+	}
+
+	return fmt.Sprintf(materialChatPrompt, title, excerpts.String(), conversation.String(), question)
+}
+
+// citationsFor mengembalikan passage yang dirujuk jawaban lewat [n]. Jika
+// LLM tidak menulis rujukan sama sekali, semua passage yang dipakai
+// dikembalikan sebagai sumber.
+func citationsFor(answer string, top []scoredPassage) []model.ChatCitation {
+	referenced := map[int]bool{}
+	for _, match := range citationRefPattern.FindAllStringSubmatch(answer, -1) {
+		number, _ := strconv.Atoi(match[1])
+		if number >= 1 && number <= len(top) {
+			referenced[number] = true
+		}
+	}
+
+	citations := []model.ChatCitation{}
+	for i, item := range top {
+		number := i + 1
+		if len(referenced) > 0 && !referenced[number] {
+			continue
+		}
+		citations = append(citations, model.ChatCitation{
+			Number:       number,
+			PassageID:    item.passage.ID,
+			Page:         item.passage.Page,
+			StartSeconds: item.passage.StartSeconds,
+			Snippet:      snippet(item.passage.Content, citationSnippetRunes),
+		})
+	}
+	return citations
+}
+
+func passageLocation(passage *model.MaterialPassage) string {
+	switch {
+	case passage.Page != nil:
+		return fmt.Sprintf("halaman %d", *passage.Page)
+	case passage.StartSeconds != nil:
+		return "menit " + formatTimestamp(*passage.StartSeconds)
+	default:
+		return fmt.Sprintf("bagian %d", passage.PassageIndex+1)
+	}
+}
+
+func snippet(text string, maxRunes int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= maxRunes {
+		return string(runes)
+	}
+	return string(runes[:maxRunes]) + "..."
+}
+
+func chatMessageToResponse(message *model.MaterialChatMessage) dto.ChatMessageResponse {
+	response := dto.ChatMessageResponse{
+		ID:        message.ID,
+		Role:      message.Role,
+		Content:   message.Content,
+		Citations: []dto.ChatCitationResponse{},
+		CreatedAt: message.CreatedAt,
+	}
+	for _, citation := range message.Citations {
+		citationResponse := dto.ChatCitationResponse{
+			Number:       citation.Number,
+			PassageID:    citation.PassageID,
+			Page:         citation.Page,
+			StartSeconds: citation.StartSeconds,
+			Snippet:      citation.Snippet,
+		}
+		if citation.StartSeconds != nil {
+			citationResponse.Timestamp = formatTimestamp(*citation.StartSeconds)
+		}
+		response.Citations = append(response.Citations, citationResponse)
+	}
+	return response
+}
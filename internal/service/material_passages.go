@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+)
+
+// materialPassageTokenBudget sengaja jauh lebih kecil dari chunk rangkuman
+// supaya hasil retrieval fokus dan sitasinya presisi.
+const materialPassageTokenBudget = 300
+
+// passageSource adalah potongan teks beserta lokasinya di materi asli.
+type passageSource struct {
+	Text         string
+	Page         *int
+	StartSeconds *int
+}
+
+func pdfPassageSources(pages []string) []passageSource {
+	sources := make([]passageSource, 0, len(pages))
+	for i, text := range pages {
+		page := i + 1
+		sources = append(sources, passageSource{Text: text, Page: &page})
+	}
+	return sources
+}
+
+// transcriptPassageSources menggabungkan baris transkrip sampai kira-kira
+// satu passage, dengan timestamp dari baris pertamanya.
+func transcriptPassageSources(segments []utils.TranscriptSegment) []passageSource {
+	var sources []passageSource
+	var current strings.Builder
+	var startSeconds int
+
+	for _, segment := range segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		if current.Len() == 0 {
+			startSeconds = int(segment.StartSeconds)
+		}
+		current.WriteString(text)
+		current.WriteString(" ")
+
+		if utils.EstimateTokens(current.String()) >= materialPassageTokenBudget {
+			start := startSeconds
+			sources = append(sources, passageSource{Text: current.String(), StartSeconds: &start})
+			current.Reset()
+		}
+	}
+	if current.Len() > 0 {
+		start := startSeconds
+		sources = append(sources, passageSource{Text: current.String(), StartSeconds: &start})
+	}
+	return sources
+}
+
+func buildPassages(materialID uint, sources []passageSource) []model.MaterialPassage {
+	var passages []model.MaterialPassage
+	for _, source := range sources {
+		for _, content := range utils.ChunkText(source.Text, materialPassageTokenBudget) {
+			passages = append(passages, model.MaterialPassage{
+				MaterialID:   materialID,
+				PassageIndex: len(passages),
+				Content:      content,
+				Page:         source.Page,
+				StartSeconds: source.StartSeconds,
+			})
+		}
+	}
+	return passages
+}
+
+// formatTimestamp mengubah detik menjadi "m:ss" atau "h:mm:ss".
+func formatTimestamp(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMaterialPageLimit = 10
+	maxMaterialPageLimit     = 100
+)
+
+type MaterialService struct {
+	matRepo *repository.MaterialRepository
+}
+
+func NewMaterialService(matRepo *repository.MaterialRepository) *MaterialService {
+	return &MaterialService{matRepo: matRepo}
+}
+
+func (s *MaterialService) GetMaterials(userIDString string, sourceType string, search string, pageQuery string, limitQuery string) (*dto.MaterialListResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	if sourceType != "" && sourceType != "pdf" && sourceType != "youtube" {
+		return nil, errors.New("source_type harus pdf atau youtube")
+	}
+
+	page := 1
+	if pageQuery != "" {
+		page, err = strconv.Atoi(pageQuery)
+		if err != nil || page < 1 {
+			return nil, errors.New("parameter page tidak valid")
+		}
+	}
+
+	limit := defaultMaterialPageLimit
+	if limitQuery != "" {
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil || limit < 1 {
+			return nil, errors.New("parameter limit tidak valid")
+		}
+		if limit > maxMaterialPageLimit {
+			limit = maxMaterialPageLimit
+		}
+	}
+
+	materials, total, err := s.matRepo.FindAllByUserID(uint(userID), sourceType, strings.TrimSpace(search), limit, (page-1)*limit)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data materi")
+	}
+
+	items := []dto.MaterialResponse{}
+	for _, material := range materials {
+		items = append(items, materialToResponse(&material))
+	}
+
+	return &dto.MaterialListResponse{
+		Items: items,
+		Page:  page,
+		Limit: limit,
+		Total: total,
+	}, nil
+}
+
+func (s *MaterialService) GetMaterial(materialIDString string, userIDString string, excerptQuery string) (*dto.MaterialDetailResponse, error) {
+	material, err := s.findOwnedMaterial(materialIDString, userIDString)
+	if err != nil {
+		return nil, err
+	}
+
+	text := []rune(material.ExtractedText)
+	response := &dto.MaterialDetailResponse{
+		MaterialResponse: materialToResponse(material),
+		TextLength:       len(text),
+	}
+
+	if excerptQuery == "" {
+		response.ExtractedText = material.ExtractedText
+		return response, nil
+	}
+
+	excerptLength, err := strconv.Atoi(excerptQuery)
+	if err != nil || excerptLength < 1 {
+		return nil, errors.New("parameter excerpt tidak valid")
+	}
+	if excerptLength < len(text) {
+		response.Excerpt = string(text[:excerptLength])
+	} else {
+		response.Excerpt = material.ExtractedText
+	}
+
+	return response, nil
+}
+
+func (s *MaterialService) RenameMaterial(materialIDString string, userIDString string, req dto.UpdateMaterialRequest) (*dto.MaterialResponse, error) {
+	material, err := s.findOwnedMaterial(materialIDString, userIDString)
+	if err != nil {
+		return nil, err
+	}
+
+	material.Title = strings.TrimSpace(req.Title)
+	updatedMaterial, err := s.matRepo.Update(material)
+	if err != nil {
+		return nil, errors.New("gagal mengupdate materi")
+	}
+
+	response := materialToResponse(updatedMaterial)
+	return &response, nil
+}
+
+func (s *MaterialService) DeleteMaterial(materialIDString string, userIDString string) error {
+	material, err := s.findOwnedMaterial(materialIDString, userIDString)
+	if err != nil {
+		return err
+	}
+
+	if err := s.matRepo.Delete(material.ID, material.UserID); err != nil {
+		return errors.New("gagal menghapus materi")
+	}
+	return nil
+}
+
+func (s *MaterialService) findOwnedMaterial(materialIDString string, userIDString string) (*model.Material, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	materialID, err := strconv.ParseUint(materialIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("material ID tidak valid")
+	}
+
+	material, err := s.matRepo.FindByID(uint(materialID), uint(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("materi tidak ditemukan atau anda tidak punya akses")
+		}
+		return nil, errors.New("gagal mengambil data materi")
+	}
+	return material, nil
+}
+
+func materialToResponse(material *model.Material) dto.MaterialResponse {
+	return dto.MaterialResponse{
+		ID:         material.ID,
+		Title:      material.Title,
+		SourceType: material.SourceType,
+		Source:     material.Source,
+		CreatedAt:  material.CreatedAt,
+	}
+}
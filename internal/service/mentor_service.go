@@ -0,0 +1,267 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"github.com/mohamadarif03/focus-room-be/pkg/utils"
+	"gorm.io/gorm"
+)
+
+const kodePembimbingLength = 8
+
+type MentorService struct {
+	userRepo *repository.UserRepository
+	taskRepo *repository.TaskRepository
+}
+
+func NewMentorService(userRepo *repository.UserRepository, taskRepo *repository.TaskRepository) *MentorService {
+	return &MentorService{userRepo: userRepo, taskRepo: taskRepo}
+}
+
+func (s *MentorService) GetCode(mentorIDString string) (*dto.MentorCodeResponse, error) {
+	mentorID, err := strconv.ParseUint(mentorIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	mentor, err := s.userRepo.FindByID(uint(mentorID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	// GET tidak membuat kode; mentor membuatnya sendiri lewat POST /pembimbing/kode.
+	if mentor.KodePembimbing == nil {
+		return nil, errors.New("kode pembimbing belum dibuat")
+	}
+
+	return &dto.MentorCodeResponse{KodePembimbing: *mentor.KodePembimbing}, nil
+}
+
+func (s *MentorService) GenerateCode(mentorIDString string) (*dto.MentorCodeResponse, error) {
+	mentorID, err := strconv.ParseUint(mentorIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	mentor, err := s.userRepo.FindByID(uint(mentorID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		code, err := utils.GenerateRandomCode(kodePembimbingLength)
+		if err != nil {
+			return nil, errors.New("gagal membuat kode pembimbing")
+		}
+
+		existing, err := s.userRepo.FindByKodePembimbing(code)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("database error")
+		}
+		if existing != nil {
+			continue
+		}
+
+		if err := s.userRepo.UpdateColumns(mentor.ID, map[string]interface{}{"kode_pembimbing": code}); err != nil {
+			return nil, errors.New("gagal menyimpan kode pembimbing")
+		}
+
+		log.Printf("[Pembimbing] User %d membuat kode pembimbing baru.", mentor.ID)
+		return &dto.MentorCodeResponse{KodePembimbing: code}, nil
+	}
+
+	return nil, errors.New("gagal membuat kode pembimbing yang unik")
+}
+
+func (s *MentorService) LinkStudent(studentIDString string, req dto.LinkMentorRequest) (*dto.MentorInfoResponse, error) {
+	studentID, err := strconv.ParseUint(studentIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	student, err := s.userRepo.FindByID(uint(studentID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(req.KodePembimbing))
+	mentor, err := s.userRepo.FindByKodePembimbing(code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("kode pembimbing tidak ditemukan")
+		}
+		return nil, errors.New("database error")
+	}
+
+	if err := s.userRepo.UpdateColumns(student.ID, map[string]interface{}{"pembimbing_id": mentor.ID}); err != nil {
+		return nil, errors.New("gagal menghubungkan ke pembimbing")
+	}
+
+	log.Printf("[Pembimbing] Siswa %d terhubung ke pembimbing %d.", student.ID, mentor.ID)
+	return &dto.MentorInfoResponse{
+		ID:       mentor.ID,
+		Username: mentor.Username,
+		Email:    mentor.Email,
+	}, nil
+}
+
+func (s *MentorService) GetStudents(mentorIDString string) ([]dto.MentorStudentResponse, error) {
+	mentorID, err := strconv.ParseUint(mentorIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	students, err := s.userRepo.FindStudentsByPembimbingID(uint(mentorID))
+	if err != nil {
+		return nil, errors.New("gagal mengambil data siswa")
+	}
+
+	now := time.Now()
+
+	studentResponses := []dto.MentorStudentResponse{}
+	for _, student := range students {
+		tasks, err := s.taskRepo.FindTasksByUserIDAndDate(student.ID, userToday(&student, now))
+		if err != nil {
+			return nil, errors.New("gagal mengambil data task siswa")
+		}
+
+		completedTasks := 0
+		for _, task := range tasks {
+			if task.IsCompleted {
+				completedTasks++
+			}
+		}
+
+		studentResponses = append(studentResponses, dto.MentorStudentResponse{
+			ID:                  student.ID,
+			Username:            student.Username,
+			Email:               student.Email,
+			CurrentStreak:       student.CurrentStreak,
+			TotalTasksToday:     len(tasks),
+			CompletedTasksToday: completedTasks,
+		})
+	}
+
+	return studentResponses, nil
+}
+
+func (s *MentorService) AssignTask(mentorIDString string, req dto.AssignTaskRequest) ([]dto.AssignedTaskResponse, error) {
+	mentorID, err := strconv.ParseUint(mentorIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	taskDate, err := time.Parse("2006-01-02", req.TaskDate)
+	if err != nil {
+		return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+	}
+
+	students, err := s.userRepo.FindStudentsByPembimbingID(uint(mentorID))
+	if err != nil {
+		return nil, errors.New("gagal mengambil data siswa")
+	}
+
+	if req.StudentID != nil {
+		var target []model.User
+		for _, student := range students {
+			if student.ID == *req.StudentID {
+				target = append(target, student)
+				break
+			}
+		}
+		if len(target) == 0 {
+			return nil, errors.New("siswa tidak ditemukan atau bukan bimbingan anda")
+		}
+		students = target
+	}
+
+	if len(students) == 0 {
+		return nil, errors.New("belum ada siswa yang terhubung")
+	}
+
+	assignerID := uint(mentorID)
+	newTasks := make([]model.Task, 0, len(students))
+	for _, student := range students {
+		newTasks = append(newTasks, model.Task{
+			Title:        req.Title,
+			TaskDate:     taskDate,
+			IsCompleted:  false,
+			UserID:       student.ID,
+			AssignedByID: &assignerID,
+		})
+	}
+
+	if err := s.taskRepo.CreateTasks(newTasks); err != nil {
+		return nil, errors.New("gagal menyimpan task ke database")
+	}
+
+	assignedTasks := make([]dto.AssignedTaskResponse, 0, len(newTasks))
+	for i, createdTask := range newTasks {
+		assignedTasks = append(assignedTasks, dto.AssignedTaskResponse{
+			ID:              createdTask.ID,
+			Title:           createdTask.Title,
+			IsCompleted:     createdTask.IsCompleted,
+			TaskDate:        createdTask.TaskDate,
+			StudentID:       students[i].ID,
+			StudentUsername: students[i].Username,
+		})
+	}
+
+	log.Printf("[Pembimbing] User %d memberikan task ke %d siswa.", mentorID, len(assignedTasks))
+	return assignedTasks, nil
+}
+
+func (s *MentorService) GetAssignedTasks(mentorIDString string, dateQuery string) (*dto.AssignedTasksResponse, error) {
+	mentorID, err := strconv.ParseUint(mentorIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	var targetDate time.Time
+	if dateQuery == "" {
+		mentor, err := s.userRepo.FindByID(uint(mentorID))
+		if err != nil {
+			return nil, errors.New("user tidak ditemukan")
+		}
+		targetDate = userToday(mentor, time.Now())
+	} else {
+		parsedDate, err := time.Parse("2006-01-02", dateQuery)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+		}
+		targetDate = parsedDate
+	}
+
+	tasks, err := s.taskRepo.FindTasksByAssignerIDAndDate(uint(mentorID), targetDate)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data task")
+	}
+
+	response := &dto.AssignedTasksResponse{
+		TaskDate: targetDate.Format("2006-01-02"),
+		Tasks:    []dto.AssignedTaskResponse{},
+	}
+	for _, task := range tasks {
+		response.TotalTasks++
+		if task.IsCompleted {
+			response.CompletedTasks++
+		}
+		response.Tasks = append(response.Tasks, dto.AssignedTaskResponse{
+			ID:              task.ID,
+			Title:           task.Title,
+			IsCompleted:     task.IsCompleted,
+			TaskDate:        task.TaskDate,
+			StudentID:       task.UserID,
+			StudentUsername: task.User.Username,
+		})
+	}
+
+	return response, nil
+}
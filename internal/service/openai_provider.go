@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+	// openAIEmbedBatchSize menjaga satu request /embeddings tetap di bawah
+	// batas jumlah input dan token per request.
+	openAIEmbedBatchSize = 100
+)
+
+// OpenAIProvider berbicara dengan endpoint /chat/completions yang kompatibel
+// dengan OpenAI, sehingga bisa juga dipakai untuk server lokal seperti
+// Ollama atau vLLM.
+type OpenAIProvider struct {
+	baseURL        string
+	apiKey         string
+	model          string
+	embeddingModel string
+	httpClient     *http.Client
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []openAIChatMessage `json:"messages"`
+	Stream   bool                `json:"stream,omitempty"`
+}
+
+// openAIStreamChunk adalah satu event "data:" dari respons stream.
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta openAIChatMessage `json:"delta"`
+	} `json:"choices"`
+}
+
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewOpenAIProvider(baseURL string, apiKey string, model string, embeddingModel string) (*OpenAIProvider, error) {
+	if model == "" {
+		return nil, errors.New("model LLM untuk provider openai belum diisi")
+	}
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+
+	return &OpenAIProvider{
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		apiKey:         apiKey,
+		model:          model,
+		embeddingModel: embeddingModel,
+		httpClient:     &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+func (p *OpenAIProvider) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return p.chat(ctx, []openAIChatMessage{
+		{Role: "user", Content: prompt},
+	})
+}
+
+func (p *OpenAIProvider) StreamText(ctx context.Context, prompt string, onChunk func(chunk string) error) (string, error) {
+	resp, err := p.do(ctx, "/chat/completions", openAIChatRequest{
+		Model:    p.model,
+		Messages: []openAIChatMessage{{Role: "user", Content: prompt}},
+		Stream:   true,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		var chatResp openAIChatResponse
+		if json.Unmarshal(respBody, &chatResp) == nil && chatResp.Error != nil {
+			return "", fmt.Errorf("LLM mengembalikan error (status %d): %s", resp.StatusCode, chatResp.Error.Message)
+		}
+		return "", fmt.Errorf("LLM mengembalikan status %d", resp.StatusCode)
+	}
+
+	var result strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", fmt.Errorf("respons stream LLM tidak valid: %w", err)
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		result.WriteString(chunk.Choices[0].Delta.Content)
+		if err := onChunk(chunk.Choices[0].Delta.Content); err != nil {
+			return "", err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("gagal membaca stream LLM: %w", err)
+	}
+	return result.String(), nil
+}
+
+// GenerateJSON mengabaikan schema karena tidak semua server kompatibel
+// OpenAI mendukung response_format json_schema; output tetap divalidasi oleh
+// pemanggil.
+func (p *OpenAIProvider) GenerateJSON(ctx context.Context, prompt string, schema *JSONSchema) (string, error) {
+	return p.chat(ctx, []openAIChatMessage{
+		{Role: "system", Content: "Jawab hanya dengan JSON yang valid tanpa teks lain dan tanpa code fence."},
+		{Role: "user", Content: prompt},
+	})
+}
+
+// Embed memanggil endpoint /embeddings. Model embedding wajib diatur lewat
+// LLM_EMBEDDING_MODEL karena tidak ada default yang berlaku untuk semua server.
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if p.embeddingModel == "" {
+		return nil, errors.New("LLM_EMBEDDING_MODEL belum diisi untuk provider openai")
+	}
+
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += openAIEmbedBatchSize {
+		end := min(start+openAIEmbedBatchSize, len(texts))
+
+		batch, err := p.embedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+func (p *OpenAIProvider) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	respBody, statusCode, err := p.post(ctx, "/embeddings", openAIEmbeddingRequest{Model: p.embeddingModel, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+
+	var embedResp openAIEmbeddingResponse
+	if err := json.Unmarshal(respBody, &embedResp); err != nil {
+		return nil, fmt.Errorf("respons embedding tidak valid (status %d): %w", statusCode, err)
+	}
+	if statusCode != http.StatusOK {
+		if embedResp.Error != nil {
+			return nil, fmt.Errorf("LLM mengembalikan error (status %d): %s", statusCode, embedResp.Error.Message)
+		}
+		return nil, fmt.Errorf("LLM mengembalikan status %d", statusCode)
+	}
+	if len(embedResp.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding mengembalikan %d vektor untuk %d teks", len(embedResp.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range embedResp.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, fmt.Errorf("indeks embedding %d tidak valid", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
+
+func (p *OpenAIProvider) chat(ctx context.Context, messages []openAIChatMessage) (string, error) {
+	respBody, statusCode, err := p.post(ctx, "/chat/completions", openAIChatRequest{Model: p.model, Messages: messages})
+	if err != nil {
+		return "", err
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return "", fmt.Errorf("respons LLM tidak valid (status %d): %w", statusCode, err)
+	}
+	if statusCode != http.StatusOK {
+		if chatResp.Error != nil {
+			return "", fmt.Errorf("LLM mengembalikan error (status %d): %s", statusCode, chatResp.Error.Message)
+		}
+		return "", fmt.Errorf("LLM mengembalikan status %d", statusCode)
+	}
+	if len(chatResp.Choices) == 0 {
+		return "", nil
+	}
+
+	return chatResp.Choices[0].Message.Content, nil
+}
+
+func (p *OpenAIProvider) post(ctx context.Context, path string, payload interface{}) ([]byte, int, error) {
+	resp, err := p.do(ctx, path, payload)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("gagal membaca respons LLM: %w", err)
+	}
+	return respBody, resp.StatusCode, nil
+}
+
+func (p *OpenAIProvider) do(ctx context.Context, path string, payload interface{}) (*http.Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memanggil LLM: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"gorm.io/gorm"
+)
+
+type QuizService struct {
+	quizRepo     *repository.QuizRepository
+	achievements *AchievementService
+	xp           *XPService
+}
+
+func NewQuizService(quizRepo *repository.QuizRepository, achievements *AchievementService, xp *XPService) *QuizService {
+	return &QuizService{quizRepo: quizRepo, achievements: achievements, xp: xp}
+}
+
+func (s *QuizService) StartAttempt(quizIDString string, userIDString string) (*dto.StartQuizAttemptResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	quizID, err := strconv.ParseUint(quizIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("quiz ID tidak valid")
+	}
+
+	quiz, err := s.quizRepo.FindByID(uint(quizID), uint(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("kuis tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil data kuis")
+	}
+
+	attempt := &model.QuizAttempt{
+		QuizID:         quiz.ID,
+		UserID:         uint(userID),
+		MaterialID:     quiz.MaterialID,
+		TotalQuestions: len(quiz.Questions),
+		StartedAt:      time.Now(),
+	}
+	savedAttempt, err := s.quizRepo.CreateAttempt(attempt)
+	if err != nil {
+		return nil, errors.New("gagal memulai kuis")
+	}
+
+	return &dto.StartQuizAttemptResponse{
+		AttemptID:  savedAttempt.ID,
+		QuizID:     quiz.ID,
+		MaterialID: quiz.MaterialID,
+		Difficulty: quiz.Difficulty,
+		StartedAt:  savedAttempt.StartedAt,
+		Questions:  quizQuestionsToResponse(quiz.Questions),
+	}, nil
+}
+
+func (s *QuizService) SubmitAttempt(attemptIDString string, userIDString string, req dto.SubmitQuizAttemptRequest) (*dto.QuizAttemptResultResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	attemptID, err := strconv.ParseUint(attemptIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("attempt ID tidak valid")
+	}
+
+	attempt, err := s.quizRepo.FindAttemptByID(uint(attemptID), uint(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attempt kuis tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil data attempt kuis")
+	}
+
+	if attempt.SubmittedAt != nil {
+		return nil, errors.New("attempt kuis sudah dikumpulkan")
+	}
+
+	submitted := make(map[uint]string, len(req.Answers))
+	for _, answer := range req.Answers {
+		submitted[answer.QuestionID] = answer.Jawaban
+	}
+
+	correct := 0
+	answers := make([]model.QuizAttemptAnswer, 0, len(attempt.Quiz.Questions))
+	for _, question := range attempt.Quiz.Questions {
+		jawaban := normalizeQuizAnswer(question.Type, submitted[question.ID])
+		isCorrect := isQuizAnswerCorrect(&question, jawaban)
+		if isCorrect {
+			correct++
+		}
+		answers = append(answers, model.QuizAttemptAnswer{
+			QuestionID: question.ID,
+			Jawaban:    jawaban,
+			IsCorrect:  isCorrect,
+		})
+	}
+
+	now := time.Now()
+	attempt.Answers = answers
+	attempt.CorrectAnswers = correct
+	attempt.TotalQuestions = len(attempt.Quiz.Questions)
+	if attempt.TotalQuestions > 0 {
+		attempt.Score = correct * 100 / attempt.TotalQuestions
+	}
+	attempt.SubmittedAt = &now
+
+	saved, err := s.quizRepo.SubmitAttempt(attempt)
+	if err != nil {
+		return nil, errors.New("gagal menyimpan hasil kuis")
+	}
+	if !saved {
+		return nil, errors.New("attempt kuis sudah dikumpulkan")
+	}
+
+	log.Printf("[Quiz] User %d mengumpulkan attempt %d. Skor: %d", userID, attempt.ID, attempt.Score)
+	s.xp.GrantQuizScore(uint(userID), attempt.QuizID, attempt.Score)
+	s.achievements.Publish(uint(userID), AchievementEventQuizSubmitted)
+
+	response := quizAttemptToResult(attempt)
+	return &response, nil
+}
+
+func (s *QuizService) GetAttempt(attemptIDString string, userIDString string) (*dto.QuizAttemptResultResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	attemptID, err := strconv.ParseUint(attemptIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("attempt ID tidak valid")
+	}
+
+	attempt, err := s.quizRepo.FindAttemptByID(uint(attemptID), uint(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attempt kuis tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil data attempt kuis")
+	}
+
+	if attempt.SubmittedAt == nil {
+		return nil, errors.New("attempt kuis belum dikumpulkan")
+	}
+
+	response := quizAttemptToResult(attempt)
+	return &response, nil
+}
+
+func (s *QuizService) GetAttemptHistory(materialIDString string, userIDString string) ([]dto.QuizAttemptHistoryResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	materialID, err := strconv.ParseUint(materialIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("material ID tidak valid")
+	}
+
+	attempts, err := s.quizRepo.FindAttemptsByMaterialID(uint(userID), uint(materialID))
+	if err != nil {
+		return nil, errors.New("gagal mengambil riwayat kuis")
+	}
+
+	history := []dto.QuizAttemptHistoryResponse{}
+	for _, attempt := range attempts {
+		history = append(history, dto.QuizAttemptHistoryResponse{
+			AttemptID:      attempt.ID,
+			QuizID:         attempt.QuizID,
+			TotalQuestions: attempt.TotalQuestions,
+			CorrectAnswers: attempt.CorrectAnswers,
+			Score:          attempt.Score,
+			StartedAt:      attempt.StartedAt,
+			SubmittedAt:    attempt.SubmittedAt,
+		})
+	}
+
+	return history, nil
+}
+
+func quizQuestionsToResponse(questions []model.QuizQuestion) []dto.QuizQuestionResponse {
+	responses := make([]dto.QuizQuestionResponse, 0, len(questions))
+	for _, q := range questions {
+		var pilihan []dto.QuizOption
+		for _, opt := range q.Pilihan {
+			pilihan = append(pilihan, dto.QuizOption(opt))
+		}
+		responses = append(responses, dto.QuizQuestionResponse{
+			ID:         q.ID,
+			Nomor:      q.Number,
+			Tipe:       q.Type,
+			Pertanyaan: q.Pertanyaan,
+			Pilihan:    pilihan,
+		})
+	}
+	return responses
+}
+
+func quizAttemptToResult(attempt *model.QuizAttempt) dto.QuizAttemptResultResponse {
+	answers := make(map[uint]model.QuizAttemptAnswer, len(attempt.Answers))
+	for _, answer := range attempt.Answers {
+		answers[answer.QuestionID] = answer
+	}
+
+	feedback := make([]dto.QuizAnswerFeedback, 0, len(attempt.Quiz.Questions))
+	for _, question := range attempt.Quiz.Questions {
+		answer := answers[question.ID]
+		feedback = append(feedback, dto.QuizAnswerFeedback{
+			QuestionID:        question.ID,
+			Nomor:             question.Number,
+			Tipe:              question.Type,
+			Pertanyaan:        question.Pertanyaan,
+			Jawaban:           answer.Jawaban,
+			JawabanBenar:      question.JawabanBenar,
+			JawabanAlternatif: question.JawabanAlternatif,
+			IsCorrect:         answer.IsCorrect,
+		})
+	}
+
+	return dto.QuizAttemptResultResponse{
+		AttemptID:      attempt.ID,
+		QuizID:         attempt.QuizID,
+		MaterialID:     attempt.MaterialID,
+		TotalQuestions: attempt.TotalQuestions,
+		CorrectAnswers: attempt.CorrectAnswers,
+		Score:          attempt.Score,
+		StartedAt:      attempt.StartedAt,
+		SubmittedAt:    attempt.SubmittedAt,
+		Feedback:       feedback,
+	}
+}
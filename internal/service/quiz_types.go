@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+)
+
+const maxQuizQuestions = 20
+
+// Error validasi komposisi kuis. Handler memetakannya ke 400 lewat errors.Is,
+// jadi pesannya boleh berubah tanpa mengubah status code.
+var (
+	ErrQuizCountRequired    = errors.New("question_count atau type_mix wajib diisi")
+	ErrQuizMixEmpty         = errors.New("type_mix harus berisi minimal satu soal")
+	ErrQuizCountMismatch    = errors.New("question_count harus sama dengan total type_mix")
+	ErrQuizTooManyQuestions = fmt.Errorf("jumlah soal maksimal %d", maxQuizQuestions)
+)
+
+// quizTypes menentukan urutan tipe soal di prompt dan saat soal dibagi ke
+// potongan materi.
+var quizTypes = []string{
+	model.QuizTypeMultipleChoice,
+	model.QuizTypeTrueFalse,
+	model.QuizTypeMultipleAnswer,
+	model.QuizTypeShortAnswer,
+	model.QuizTypeFillBlank,
+}
+
+var quizTypeGuides = map[string]string{
+	model.QuizTypeMultipleChoice: `pilihan ganda, tepat 4 pilihan A–D dan "jawaban_benar" berisi satu huruf (A, B, C, atau D)`,
+	model.QuizTypeTrueFalse:      `pernyataan benar/salah tanpa "pilihan", "jawaban_benar" berisi "benar" atau "salah"`,
+	model.QuizTypeMultipleAnswer: `pilihan ganda dengan dua atau lebih jawaban benar, tepat 4 pilihan A–D dan "jawaban_benar" berisi huruf dipisah koma, misalnya "A,C"`,
+	model.QuizTypeShortAnswer:    `isian singkat tanpa "pilihan", "jawaban_benar" berisi satu kata atau frasa pendek dan "jawaban_alternatif" berisi ejaan atau sinonim lain yang juga benar`,
+	model.QuizTypeFillBlank:      `kalimat rumpang dari materi dengan tepat satu bagian kosong ditulis "___", "jawaban_benar" berisi kata yang hilang dan "jawaban_alternatif" berisi sinonim lain yang juga benar`,
+}
+
+// quizDifficultyGuides memetakan tingkat kesulitan ke level taksonomi Bloom
+// yang diminta di prompt.
+var quizDifficultyGuides = map[string]string{
+	model.QuizDifficultyEasy:   "mudah — menguji kemampuan mengingat dan memahami konsep dasar (Bloom: remember, understand)",
+	model.QuizDifficultyMedium: "sedang — menguji pemahaman dan penerapan konsep pada contoh (Bloom: understand, apply)",
+	model.QuizDifficultyHard:   "sulit — menguji analisis dan evaluasi, misalnya membandingkan konsep atau menilai kasus (Bloom: analyze, evaluate)",
+}
+
+// resolveQuizMix menghitung jumlah soal per tipe dari request. Tanpa
+// type_mix, semua soal berupa pilihan ganda seperti sebelumnya.
+func resolveQuizMix(req dto.GenerateQuizRequest) (map[string]int, error) {
+	mix := map[string]int{}
+	for questionType, count := range req.TypeMix {
+		if count > 0 {
+			mix[questionType] = count
+		}
+	}
+
+	total := quizMixTotal(mix)
+	switch {
+	case len(req.TypeMix) == 0 && req.QuestionCount == 0:
+		return nil, ErrQuizCountRequired
+	case len(req.TypeMix) == 0:
+		mix[model.QuizTypeMultipleChoice] = req.QuestionCount
+		total = req.QuestionCount
+	case total == 0:
+		return nil, ErrQuizMixEmpty
+	case req.QuestionCount != 0 && req.QuestionCount != total:
+		return nil, ErrQuizCountMismatch
+	}
+
+	if total > maxQuizQuestions {
+		return nil, ErrQuizTooManyQuestions
+	}
+	return mix, nil
+}
+
+func quizMixTotal(mix map[string]int) int {
+	total := 0
+	for _, count := range mix {
+		total += count
+	}
+	return total
+}
+
+// interleaveQuizTypes menyusun tipe soal secara bergiliran, sehingga setiap
+// potongan materi mendapat campuran tipe yang merata.
+func interleaveQuizTypes(mix map[string]int) []string {
+	remaining := maps.Clone(mix)
+	total := quizMixTotal(mix)
+
+	order := make([]string, 0, total)
+	for len(order) < total {
+		for _, questionType := range quizTypes {
+			if remaining[questionType] > 0 {
+				order = append(order, questionType)
+				remaining[questionType]--
+			}
+		}
+	}
+	return order
+}
+
+// normalizeQuizAnswer merapikan jawaban siswa sesuai tipe soal supaya bisa
+// dibandingkan dengan JawabanBenar yang tersimpan.
+func normalizeQuizAnswer(questionType string, jawaban string) string {
+	jawaban = strings.TrimSpace(jawaban)
+
+	switch questionType {
+	case model.QuizTypeShortAnswer, model.QuizTypeFillBlank:
+		return jawaban
+	case model.QuizTypeTrueFalse:
+		switch strings.ToUpper(jawaban) {
+		case "BENAR", "TRUE", "B":
+			return "BENAR"
+		case "SALAH", "FALSE", "S":
+			return "SALAH"
+		}
+		return strings.ToUpper(jawaban)
+	case model.QuizTypeMultipleAnswer:
+		return strings.Join(answerLetters(jawaban), ",")
+	default:
+		return strings.ToUpper(jawaban)
+	}
+}
+
+func isQuizAnswerCorrect(question *model.QuizQuestion, jawaban string) bool {
+	if jawaban == "" {
+		return false
+	}
+
+	switch question.Type {
+	case model.QuizTypeShortAnswer, model.QuizTypeFillBlank:
+		given := normalizeAnswerText(jawaban)
+		if given == normalizeAnswerText(question.JawabanBenar) {
+			return true
+		}
+		for _, alternative := range question.JawabanAlternatif {
+			if given == normalizeAnswerText(alternative) {
+				return true
+			}
+		}
+		return false
+	default:
+		return jawaban == question.JawabanBenar
+	}
+}
+
+// answerLetters memecah jawaban seperti "c, a" menjadi huruf unik yang
+// terurut: ["A", "C"].
+func answerLetters(jawaban string) []string {
+	var letters []string
+	for _, part := range strings.FieldsFunc(jawaban, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		letter := strings.ToUpper(part)
+		if !slices.Contains(letters, letter) {
+			letters = append(letters, letter)
+		}
+	}
+	slices.Sort(letters)
+	return letters
+}
+
+// normalizeAnswerText mengabaikan huruf besar/kecil, tanda baca, dan spasi
+// berlebih saat mencocokkan jawaban teks.
+func normalizeAnswerText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(words, " ")
+}
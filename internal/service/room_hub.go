@@ -0,0 +1,314 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+)
+
+const (
+	RoomStatusFocusing = "focusing"
+	RoomStatusBreak    = "break"
+
+	RoomTimerIdle  = "idle"
+	RoomTimerFocus = "focus"
+	RoomTimerBreak = "break"
+
+	roomEventBuffer = 8
+)
+
+// RoomEvent adalah satu pesan yang dikirim ke client lewat SSE. Name dipakai
+// sebagai nama event, Data di-encode sebagai JSON.
+type RoomEvent struct {
+	Name string
+	Data interface{}
+}
+
+// RoomSubscription mewakili satu koneksi client ke sebuah room. Satu user
+// boleh punya beberapa koneksi (misalnya dua tab), tapi presence-nya dihitung
+// sekali.
+type RoomSubscription struct {
+	RoomID uint
+	UserID uint
+	Events <-chan RoomEvent
+	events chan RoomEvent
+}
+
+type roomMember struct {
+	state       dto.RoomMemberState
+	connections int
+}
+
+type roomState struct {
+	focusDuration time.Duration
+	breakDuration time.Duration
+	members       map[uint]*roomMember
+	subscribers   map[*RoomSubscription]struct{}
+	timer         dto.RoomTimerState
+	phaseTimer    *time.Timer
+}
+
+// RoomHub menyimpan state realtime semua room yang sedang ada penghuninya di
+// memori proses API. State sebuah room dibuat saat koneksi pertama masuk dan
+// dibuang saat koneksi terakhir putus.
+type RoomHub struct {
+	mu    sync.Mutex
+	rooms map[uint]*roomState
+}
+
+func NewRoomHub() *RoomHub {
+	return &RoomHub{rooms: make(map[uint]*roomState)}
+}
+
+func (h *RoomHub) Join(room *model.Room, user *model.User) *RoomSubscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.rooms[room.ID]
+	if !ok {
+		state = &roomState{
+			focusDuration: time.Duration(room.FocusMinutes) * time.Minute,
+			breakDuration: time.Duration(room.BreakMinutes) * time.Minute,
+			members:       make(map[uint]*roomMember),
+			subscribers:   make(map[*RoomSubscription]struct{}),
+			timer:         dto.RoomTimerState{Phase: RoomTimerIdle},
+		}
+		h.rooms[room.ID] = state
+	}
+
+	member, ok := state.members[user.ID]
+	if !ok {
+		status := RoomStatusFocusing
+		if state.timer.Phase == RoomTimerBreak {
+			status = RoomStatusBreak
+		}
+		member = &roomMember{state: dto.RoomMemberState{
+			UserID:   user.ID,
+			Username: user.Username,
+			Status:   status,
+			JoinedAt: time.Now(),
+		}}
+		state.members[user.ID] = member
+	}
+	member.connections++
+
+	events := make(chan RoomEvent, roomEventBuffer)
+	sub := &RoomSubscription{RoomID: room.ID, UserID: user.ID, Events: events, events: events}
+	state.subscribers[sub] = struct{}{}
+
+	h.broadcastLocked(room.ID, state)
+	return sub
+}
+
+func (h *RoomHub) Leave(sub *RoomSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.rooms[sub.RoomID]
+	if !ok {
+		return
+	}
+	if _, ok := state.subscribers[sub]; !ok {
+		return
+	}
+	delete(state.subscribers, sub)
+	close(sub.events)
+
+	if member, ok := state.members[sub.UserID]; ok {
+		member.connections--
+		if member.connections <= 0 {
+			delete(state.members, sub.UserID)
+		}
+	}
+
+	if len(state.subscribers) == 0 {
+		if state.phaseTimer != nil {
+			state.phaseTimer.Stop()
+		}
+		delete(h.rooms, sub.RoomID)
+		log.Printf("[Room] Room %d kosong, state dibersihkan.", sub.RoomID)
+		return
+	}
+
+	h.broadcastLocked(sub.RoomID, state)
+}
+
+// CloseRoom memutus semua koneksi ke room, dipakai saat room dihapus.
+func (h *RoomHub) CloseRoom(roomID uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.rooms[roomID]
+	if !ok {
+		return
+	}
+	if state.phaseTimer != nil {
+		state.phaseTimer.Stop()
+	}
+	for sub := range state.subscribers {
+		close(sub.events)
+	}
+	delete(h.rooms, roomID)
+}
+
+func (h *RoomHub) SetStatus(roomID, userID uint, status string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, member, err := h.memberLocked(roomID, userID)
+	if err != nil {
+		return err
+	}
+	member.state.Status = status
+
+	h.broadcastLocked(roomID, state)
+	return nil
+}
+
+func (h *RoomHub) StartTimer(roomID, userID uint) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, _, err := h.memberLocked(roomID, userID)
+	if err != nil {
+		return err
+	}
+
+	h.startPhaseLocked(roomID, state, RoomTimerFocus, 1)
+	return nil
+}
+
+func (h *RoomHub) StopTimer(roomID, userID uint) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, _, err := h.memberLocked(roomID, userID)
+	if err != nil {
+		return err
+	}
+
+	if state.phaseTimer != nil {
+		state.phaseTimer.Stop()
+		state.phaseTimer = nil
+	}
+	state.timer = dto.RoomTimerState{Phase: RoomTimerIdle}
+
+	h.broadcastLocked(roomID, state)
+	return nil
+}
+
+func (h *RoomHub) Snapshot(roomID uint) dto.RoomStateResponse {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.rooms[roomID]
+	if !ok {
+		return dto.RoomStateResponse{
+			RoomID:     roomID,
+			Members:    []dto.RoomMemberState{},
+			Timer:      dto.RoomTimerState{Phase: RoomTimerIdle},
+			ServerTime: time.Now(),
+		}
+	}
+	return snapshotLocked(roomID, state)
+}
+
+func (h *RoomHub) OnlineCount(roomID uint) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if state, ok := h.rooms[roomID]; ok {
+		return len(state.members)
+	}
+	return 0
+}
+
+func (h *RoomHub) memberLocked(roomID, userID uint) (*roomState, *roomMember, error) {
+	state, ok := h.rooms[roomID]
+	if !ok {
+		return nil, nil, errors.New("anda belum bergabung di room ini")
+	}
+	member, ok := state.members[userID]
+	if !ok {
+		return nil, nil, errors.New("anda belum bergabung di room ini")
+	}
+	return state, member, nil
+}
+
+// startPhaseLocked memulai fase timer baru dan menjadwalkan pergantian fase
+// berikutnya. Status semua anggota ikut disamakan dengan fase timer.
+func (h *RoomHub) startPhaseLocked(roomID uint, state *roomState, phase string, cycle int) {
+	duration := state.focusDuration
+	status := RoomStatusFocusing
+	if phase == RoomTimerBreak {
+		duration = state.breakDuration
+		status = RoomStatusBreak
+	}
+
+	now := time.Now()
+	endsAt := now.Add(duration)
+	state.timer = dto.RoomTimerState{Phase: phase, Cycle: cycle, StartedAt: &now, EndsAt: &endsAt}
+	for _, member := range state.members {
+		member.state.Status = status
+	}
+
+	if state.phaseTimer != nil {
+		state.phaseTimer.Stop()
+	}
+	state.phaseTimer = time.AfterFunc(duration, func() {
+		h.advancePhase(roomID, state, endsAt)
+	})
+
+	h.broadcastLocked(roomID, state)
+}
+
+func (h *RoomHub) advancePhase(roomID uint, expected *roomState, endsAt time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.rooms[roomID]
+	if !ok || state != expected || state.timer.EndsAt == nil || !state.timer.EndsAt.Equal(endsAt) {
+		return
+	}
+
+	if state.timer.Phase == RoomTimerFocus {
+		h.startPhaseLocked(roomID, state, RoomTimerBreak, state.timer.Cycle)
+		return
+	}
+	h.startPhaseLocked(roomID, state, RoomTimerFocus, state.timer.Cycle+1)
+}
+
+// broadcastLocked mengirim snapshot terbaru ke semua koneksi. Client yang
+// buffer-nya penuh dilewati; snapshot berikutnya tetap membawa state lengkap.
+func (h *RoomHub) broadcastLocked(roomID uint, state *roomState) {
+	event := RoomEvent{Name: "state", Data: snapshotLocked(roomID, state)}
+	for sub := range state.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			log.Printf("[Room] Buffer koneksi user %d di room %d penuh, event dilewati.", sub.UserID, roomID)
+		}
+	}
+}
+
+func snapshotLocked(roomID uint, state *roomState) dto.RoomStateResponse {
+	members := make([]dto.RoomMemberState, 0, len(state.members))
+	for _, member := range state.members {
+		members = append(members, member.state)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].JoinedAt.Before(members[j].JoinedAt)
+	})
+
+	return dto.RoomStateResponse{
+		RoomID:     roomID,
+		Members:    members,
+		Timer:      state.timer,
+		ServerTime: time.Now(),
+	}
+}
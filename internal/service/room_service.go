@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRoomFocusMinutes = 25
+	defaultRoomBreakMinutes = 5
+)
+
+type RoomService struct {
+	roomRepo *repository.RoomRepository
+	userRepo *repository.UserRepository
+	hub      *RoomHub
+}
+
+func NewRoomService(roomRepo *repository.RoomRepository, userRepo *repository.UserRepository, hub *RoomHub) *RoomService {
+	return &RoomService{roomRepo: roomRepo, userRepo: userRepo, hub: hub}
+}
+
+func (s *RoomService) CreateRoom(userIDString string, req dto.CreateRoomRequest) (*dto.RoomResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	room := &model.Room{
+		Name:         req.Name,
+		OwnerID:      uint(userID),
+		FocusMinutes: req.FocusMinutes,
+		BreakMinutes: req.BreakMinutes,
+	}
+	if room.FocusMinutes == 0 {
+		room.FocusMinutes = defaultRoomFocusMinutes
+	}
+	if room.BreakMinutes == 0 {
+		room.BreakMinutes = defaultRoomBreakMinutes
+	}
+
+	if _, err := s.roomRepo.Create(room); err != nil {
+		return nil, errors.New("gagal membuat room")
+	}
+
+	savedRoom, err := s.roomRepo.FindByID(room.ID)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data room")
+	}
+
+	log.Printf("[Room] User %d membuat room %d.", userID, savedRoom.ID)
+	response := s.roomToResponse(savedRoom)
+	return &response, nil
+}
+
+func (s *RoomService) GetRooms() ([]dto.RoomResponse, error) {
+	rooms, err := s.roomRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("gagal mengambil data room")
+	}
+
+	responses := []dto.RoomResponse{}
+	for _, room := range rooms {
+		responses = append(responses, s.roomToResponse(&room))
+	}
+	return responses, nil
+}
+
+func (s *RoomService) GetRoomState(roomIDString string) (*dto.RoomStateResponse, error) {
+	room, err := s.findRoom(roomIDString)
+	if err != nil {
+		return nil, err
+	}
+
+	state := s.hub.Snapshot(room.ID)
+	return &state, nil
+}
+
+func (s *RoomService) DeleteRoom(roomIDString string, userIDString string) error {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return errors.New("user ID tidak valid")
+	}
+	room, err := s.findRoom(roomIDString)
+	if err != nil {
+		return err
+	}
+	if room.OwnerID != uint(userID) {
+		return errors.New("akses ditolak: hanya pembuat room yang bisa menghapus")
+	}
+
+	if _, err := s.roomRepo.Delete(room.ID, uint(userID)); err != nil {
+		return errors.New("gagal menghapus room")
+	}
+	s.hub.CloseRoom(room.ID)
+	return nil
+}
+
+// Join mendaftarkan koneksi realtime user ke room. Pemanggil wajib memanggil
+// Leave saat koneksi selesai supaya presence dan state room ikut dibersihkan.
+func (s *RoomService) Join(roomIDString string, userIDString string) (*RoomSubscription, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+	room, err := s.findRoom(roomIDString)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(uint(userID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	log.Printf("[Room] User %d bergabung ke room %d.", user.ID, room.ID)
+	return s.hub.Join(room, user), nil
+}
+
+func (s *RoomService) Leave(sub *RoomSubscription) {
+	s.hub.Leave(sub)
+	log.Printf("[Room] User %d keluar dari room %d.", sub.UserID, sub.RoomID)
+}
+
+func (s *RoomService) UpdateStatus(roomIDString string, userIDString string, req dto.UpdateRoomStatusRequest) error {
+	roomID, userID, err := parseRoomAndUserID(roomIDString, userIDString)
+	if err != nil {
+		return err
+	}
+	return s.hub.SetStatus(roomID, userID, req.Status)
+}
+
+func (s *RoomService) StartTimer(roomIDString string, userIDString string) error {
+	roomID, userID, err := parseRoomAndUserID(roomIDString, userIDString)
+	if err != nil {
+		return err
+	}
+	return s.hub.StartTimer(roomID, userID)
+}
+
+func (s *RoomService) StopTimer(roomIDString string, userIDString string) error {
+	roomID, userID, err := parseRoomAndUserID(roomIDString, userIDString)
+	if err != nil {
+		return err
+	}
+	return s.hub.StopTimer(roomID, userID)
+}
+
+// MemberIDs mengembalikan pemilik room dan user yang sedang online di room.
+// Room tidak punya keanggotaan permanen, jadi inilah definisi anggota room.
+func (s *RoomService) MemberIDs(roomIDString string) ([]uint, error) {
+	room, err := s.findRoom(roomIDString)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs := []uint{room.OwnerID}
+	for _, member := range s.hub.Snapshot(room.ID).Members {
+		if member.UserID != room.OwnerID {
+			memberIDs = append(memberIDs, member.UserID)
+		}
+	}
+	return memberIDs, nil
+}
+
+func (s *RoomService) findRoom(roomIDString string) (*model.Room, error) {
+	roomID, err := strconv.ParseUint(roomIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("room ID tidak valid")
+	}
+
+	room, err := s.roomRepo.FindByID(uint(roomID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("room tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil data room")
+	}
+	return room, nil
+}
+
+func (s *RoomService) roomToResponse(room *model.Room) dto.RoomResponse {
+	return dto.RoomResponse{
+		ID:           room.ID,
+		Name:         room.Name,
+		OwnerID:      room.OwnerID,
+		OwnerName:    room.Owner.Username,
+		FocusMinutes: room.FocusMinutes,
+		BreakMinutes: room.BreakMinutes,
+		OnlineCount:  s.hub.OnlineCount(room.ID),
+		CreatedAt:    room.CreatedAt,
+	}
+}
+
+func parseRoomAndUserID(roomIDString string, userIDString string) (uint, uint, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return 0, 0, errors.New("user ID tidak valid")
+	}
+	roomID, err := strconv.ParseUint(roomIDString, 10, 32)
+	if err != nil {
+		return 0, 0, errors.New("room ID tidak valid")
+	}
+	return uint(roomID), uint(userID), nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/config"
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+)
+
+// DefaultTimeZone dipakai untuk user yang belum mengatur zona waktunya.
+const DefaultTimeZone = "Asia/Jakarta"
+
+var locationCache sync.Map
+
+// StreakEngine adalah satu-satunya tempat aturan streak dihitung. Semua
+// perhitungan "hari ini" dan "kemarin" memakai zona waktu masing-masing user,
+// bukan zona waktu server maupun database.
+//
+// Setiap perubahan dicatat di ledger StreakEvent. Tiap kelipatan
+// FreezeEveryDays hari streak, user mendapat satu freeze token (maksimal
+// MaxFreezes) yang otomatis dipakai untuk menahan reset saat satu hari
+// terlewat.
+//
+// Perubahan streak selalu diputuskan ulang di dalam transaksi yang mengunci
+// baris user (UserRepository.UpdateStreak), jadi H-0 dan H-1 yang berjalan
+// bersamaan tetap hanya mengubah streak sekali.
+type StreakEngine struct {
+	userRepo        *repository.UserRepository
+	taskRepo        *repository.TaskRepository
+	recurrenceRepo  *repository.TaskRecurrenceRepository
+	xp              *XPService
+	freezeEveryDays int
+	maxFreezes      int
+}
+
+func NewStreakEngine(userRepo *repository.UserRepository, taskRepo *repository.TaskRepository, recurrenceRepo *repository.TaskRecurrenceRepository, xp *XPService, cfg config.StreakConfig) *StreakEngine {
+	return &StreakEngine{
+		userRepo:        userRepo,
+		taskRepo:        taskRepo,
+		recurrenceRepo:  recurrenceRepo,
+		xp:              xp,
+		freezeEveryDays: cfg.FreezeEveryDays,
+		maxFreezes:      cfg.MaxFreezes,
+	}
+}
+
+// AwardToday (H-0) menaikkan streak saat semua task hari ini selesai. Task
+// yang diselesaikan untuk tanggal lain tidak memberi imbalan. Hasilnya adalah
+// state streak user setelah pengecekan.
+//
+// Hari kemarin (H-1) selalu dievaluasi lebih dulu, supaya reset H-1 yang
+// datang belakangan tidak menghapus imbalan hari ini.
+func (e *StreakEngine) AwardToday(userID uint, taskDate time.Time, now time.Time) (*dto.StreakStateResponse, error) {
+	log.Printf("[Streak H-0] User %d menyelesaikan task. Memeriksa...", userID)
+
+	user, err := e.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := e.evaluateYesterday(user, now); err != nil {
+		return nil, err
+	}
+
+	loc := userLocation(user)
+	today, _ := streakDates(now, loc)
+
+	if !civilDate(taskDate).Equal(today) {
+		log.Printf("[Streak H-0] User %d menyelesaikan task lama. Streak H-0 diabaikan.", userID)
+		return streakState(user, today), nil
+	}
+
+	totalTasks, completedTasks, err := e.countDay(userID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	if totalTasks == 0 || totalTasks != completedTasks {
+		log.Printf("[Streak H-0] User %d belum selesai. (Total: %d, Selesai: %d)", userID, totalTasks, completedTasks)
+		return streakState(user, today), nil
+	}
+
+	var awarded bool
+	user, err = e.userRepo.UpdateStreak(userID, func(locked *model.User) (bool, []model.StreakEvent) {
+		events := e.applyAward(locked, today, now)
+		awarded = len(events) > 0
+		return awarded, events
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal update streak user %d: %w", userID, err)
+	}
+
+	if awarded {
+		log.Printf("[Streak H-0] SUKSES! User %d menyelesaikan semua task H-0. Streak naik ke %d.", userID, user.CurrentStreak)
+		e.xp.GrantStreakDay(userID, today)
+	} else {
+		log.Printf("[Streak H-0] User %d sudah dapat imbalan hari ini. Diabaikan.", userID)
+	}
+	return streakState(user, today), nil
+}
+
+// applyAward menaikkan streak user yang sudah terkunci untuk hari today.
+// Tidak ada perubahan (dan tidak ada event) jika imbalan hari itu sudah
+// diberikan.
+func (e *StreakEngine) applyAward(user *model.User, today time.Time, now time.Time) []model.StreakEvent {
+	if isLocalDay(user.LastStreakAwardedDate, today, userLocation(user)) {
+		return nil
+	}
+
+	streakBefore := user.CurrentStreak
+	user.CurrentStreak += 1
+	user.LongestStreak = max(user.LongestStreak, user.CurrentStreak)
+	user.LastStreakAwardedDate = &now
+
+	events := []model.StreakEvent{
+		newStreakEvent(user, model.StreakEventAwarded, today, streakBefore),
+	}
+
+	if e.freezeEveryDays > 0 && user.CurrentStreak%e.freezeEveryDays == 0 && user.StreakFreezes < e.maxFreezes {
+		user.StreakFreezes++
+		events = append(events, newStreakEvent(user, model.StreakEventFreezeEarned, today, user.CurrentStreak))
+		log.Printf("[Streak H-0] User %d mendapat freeze token (%d).", user.ID, user.StreakFreezes)
+	}
+
+	return events
+}
+
+// CheckYesterday (H-1) me-reset streak jika task kemarin tidak ada atau tidak
+// selesai semua. Pengecekan hanya dilakukan sekali per hari lokal user.
+func (e *StreakEngine) CheckYesterday(user *model.User, now time.Time) (*model.User, error) {
+	if _, _, err := e.evaluateYesterday(user, now); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// evaluateYesterday adalah inti CheckYesterday. checked bernilai false jika
+// user sudah dicek di hari lokal yang sama (idempoten lewat
+// LastStreakCheckDate). Data user diperbarui dengan hasil dari database.
+func (e *StreakEngine) evaluateYesterday(user *model.User, now time.Time) (checked bool, reset bool, err error) {
+	loc := userLocation(user)
+	today, yesterday := streakDates(now, loc)
+
+	if isLocalDay(user.LastStreakCheckDate, today, loc) {
+		log.Printf("[Streak H-1] User %d sudah dicek hari ini. Tidak ada update.", user.ID)
+		return false, false, nil
+	}
+
+	log.Printf("[Streak H-1] User %d belum dicek. Mengecek tugas H-1 (%s, %s)...", user.ID, yesterday.Format("2006-01-02"), loc)
+
+	totalTasks, completedTasks, err := e.countDay(user.ID, yesterday)
+	if err != nil {
+		return false, false, fmt.Errorf("gagal ambil task user %d: %w", user.ID, err)
+	}
+	missed := totalTasks == 0 || completedTasks != totalTasks
+
+	updated, err := e.userRepo.UpdateStreak(user.ID, func(locked *model.User) (bool, []model.StreakEvent) {
+		checked, reset = false, false
+		if isLocalDay(locked.LastStreakCheckDate, today, loc) {
+			return false, nil
+		}
+
+		checked = true
+		var events []model.StreakEvent
+		events, reset = applyMissedDay(locked, yesterday, missed)
+		locked.LastStreakCheckDate = &now
+		return true, events
+	})
+	if err != nil {
+		return false, false, fmt.Errorf("gagal update streak user %d: %w", user.ID, err)
+	}
+	*user = *updated
+
+	switch {
+	case !checked:
+		log.Printf("[Streak H-1] User %d sudah dicek oleh proses lain. Tidak ada update.", user.ID)
+	case reset:
+		log.Printf("[Streak H-1] User %d: Tugas H-1 tidak selesai (Total: %d, Selesai: %d). Streak di-reset ke 0.", user.ID, totalTasks, completedTasks)
+	case missed && user.CurrentStreak > 0:
+		log.Printf("[Streak H-1] User %d: Tugas H-1 terlewat. Freeze token dipakai, streak %d aman.", user.ID, user.CurrentStreak)
+	default:
+		log.Printf("[Streak H-1] Pengecekan User %d selesai, tidak ada reset.", user.ID)
+	}
+
+	return checked, reset, nil
+}
+
+// applyMissedDay menerapkan hasil hari kemarin ke user yang sudah terkunci.
+// Hari yang terlewat memakai satu freeze token jika ada; jika tidak, streak
+// di-reset ke 0.
+func applyMissedDay(user *model.User, yesterday time.Time, missed bool) ([]model.StreakEvent, bool) {
+	if !missed || user.CurrentStreak == 0 {
+		return nil, false
+	}
+
+	streakBefore := user.CurrentStreak
+	user.LongestStreak = max(user.LongestStreak, user.CurrentStreak)
+
+	if user.StreakFreezes > 0 {
+		user.StreakFreezes--
+		return []model.StreakEvent{newStreakEvent(user, model.StreakEventFreezeUsed, yesterday, streakBefore)}, false
+	}
+
+	user.CurrentStreak = 0
+	return []model.StreakEvent{newStreakEvent(user, model.StreakEventReset, yesterday, streakBefore)}, true
+}
+
+// UserToday mengembalikan tanggal hari ini menurut zona waktu user.
+func (e *StreakEngine) UserToday(userID uint, now time.Time) (time.Time, error) {
+	user, err := e.userRepo.FindByID(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return userToday(user, now), nil
+}
+
+func newStreakEvent(user *model.User, eventType string, day time.Time, streakBefore int) model.StreakEvent {
+	return model.StreakEvent{
+		UserID:       user.ID,
+		Type:         eventType,
+		Day:          day,
+		StreakBefore: streakBefore,
+		StreakAfter:  user.CurrentStreak,
+		FreezesAfter: user.StreakFreezes,
+	}
+}
+
+func streakState(user *model.User, today time.Time) *dto.StreakStateResponse {
+	return &dto.StreakStateResponse{
+		CurrentStreak: user.CurrentStreak,
+		LongestStreak: max(user.LongestStreak, user.CurrentStreak),
+		StreakFreezes: user.StreakFreezes,
+		AwardedToday:  isLocalDay(user.LastStreakAwardedDate, today, userLocation(user)),
+	}
+}
+
+func (e *StreakEngine) countDay(userID uint, day time.Time) (int, int, error) {
+	if err := materializeRecurrences(e.taskRepo, e.recurrenceRepo, userID, day, day); err != nil {
+		log.Printf("[Streak] Gagal menyiapkan task berulang user %d: %v", userID, err)
+	}
+
+	tasks, err := e.taskRepo.FindTasksByUserIDAndDate(userID, day)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	completed := 0
+	for _, task := range tasks {
+		if task.IsCompleted {
+			completed++
+		}
+	}
+	return len(tasks), completed, nil
+}
+
+// LoadTimeZone memvalidasi nama zona waktu IANA, misalnya "Asia/Jakarta",
+// "Asia/Makassar", atau "Asia/Jayapura".
+func LoadTimeZone(name string) (*time.Location, error) {
+	if cached, ok := locationCache.Load(name); ok {
+		return cached.(*time.Location), nil
+	}
+	if name == "" || name == "Local" {
+		return nil, errors.New("zona waktu tidak valid")
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("zona waktu tidak valid")
+	}
+	locationCache.Store(name, loc)
+	return loc, nil
+}
+
+func userLocation(user *model.User) *time.Location {
+	if loc, err := LoadTimeZone(user.TimeZone); err == nil {
+		return loc
+	}
+	loc, _ := LoadTimeZone(DefaultTimeZone)
+	return loc
+}
+
+func userToday(user *model.User, now time.Time) time.Time {
+	today, _ := streakDates(now, userLocation(user))
+	return today
+}
+
+// streakDates menghitung tanggal kalender hari ini dan kemarin di zona loc.
+// Hasilnya berupa tanggal (tengah malam UTC) sehingga aman dibandingkan dan
+// dikurangi satu hari tanpa terpengaruh DST.
+func streakDates(now time.Time, loc *time.Location) (time.Time, time.Time) {
+	today := civilDate(now.In(loc))
+	return today, today.AddDate(0, 0, -1)
+}
+
+// isLocalDay mengecek apakah timestamp t jatuh di tanggal day menurut zona loc.
+func isLocalDay(t *time.Time, day time.Time, loc *time.Location) bool {
+	if t == nil {
+		return false
+	}
+	return civilDate(t.In(loc)).Equal(day)
+}
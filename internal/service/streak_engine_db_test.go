@@ -0,0 +1,172 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/config"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+	"gorm.io/gorm"
+)
+
+const streakRaceGoroutines = 10
+
+func newTestStreakEngine(db *gorm.DB) *StreakEngine {
+	userRepo := repository.NewUserRepository(db)
+	rooms := NewRoomService(repository.NewRoomRepository(db), userRepo, NewRoomHub())
+	xp := NewXPService(repository.NewXPRepository(db), userRepo, rooms)
+	return NewStreakEngine(userRepo, repository.NewTaskRepository(db), repository.NewTaskRecurrenceRepository(db), xp,
+		config.StreakConfig{FreezeEveryDays: 7, MaxFreezes: 2})
+}
+
+func createTestTask(t *testing.T, db *gorm.DB, userID uint, day time.Time, completed bool) {
+	t.Helper()
+	task := &model.Task{Title: "task test", TaskDate: day, UserID: userID, IsCompleted: completed}
+	if err := db.Omit("User", "AssignedBy", "Recurrence").Create(task).Error; err != nil {
+		t.Fatalf("gagal membuat task test: %v", err)
+	}
+}
+
+func countStreakEvents(t *testing.T, db *gorm.DB, userID uint, eventType string) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&model.StreakEvent{}).Where("user_id = ? AND type = ?", userID, eventType).Count(&count).Error; err != nil {
+		t.Fatalf("gagal menghitung streak event: %v", err)
+	}
+	return count
+}
+
+func reloadUser(t *testing.T, db *gorm.DB, userID uint) *model.User {
+	t.Helper()
+	var user model.User
+	if err := db.First(&user, userID).Error; err != nil {
+		t.Fatalf("gagal mengambil user: %v", err)
+	}
+	return &user
+}
+
+// runConcurrently menjalankan semua fn bersamaan dan menunggu sampai selesai.
+func runConcurrently(t *testing.T, fns ...func() error) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, len(fns))
+	for _, fn := range fns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs <- fn()
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("goroutine gagal: %v", err)
+		}
+	}
+}
+
+func TestAwardTodayConcurrentIncrementsOnce(t *testing.T) {
+	db := openTestDB(t)
+	engine := newTestStreakEngine(db)
+
+	now := time.Now()
+	loc, _ := LoadTimeZone(DefaultTimeZone)
+	today, yesterday := streakDates(now, loc)
+
+	checkedAt := now
+	user := createTestStudent(t, db, func(u *model.User) {
+		u.CurrentStreak = 3
+		u.LongestStreak = 3
+		u.LastStreakCheckDate = &checkedAt
+	})
+	createTestTask(t, db, user.ID, yesterday, true)
+	createTestTask(t, db, user.ID, today, true)
+
+	fns := make([]func() error, streakRaceGoroutines)
+	for i := range fns {
+		fns[i] = func() error {
+			_, err := engine.AwardToday(user.ID, today, now)
+			return err
+		}
+	}
+	runConcurrently(t, fns...)
+
+	if got := reloadUser(t, db, user.ID).CurrentStreak; got != 4 {
+		t.Errorf("CurrentStreak = %d, want 4", got)
+	}
+	if got := countStreakEvents(t, db, user.ID, model.StreakEventAwarded); got != 1 {
+		t.Errorf("jumlah event awarded = %d, want 1", got)
+	}
+}
+
+func TestAwardTodayRacesEvaluateYesterday(t *testing.T) {
+	db := openTestDB(t)
+	engine := newTestStreakEngine(db)
+
+	now := time.Now()
+	loc, _ := LoadTimeZone(DefaultTimeZone)
+	today, yesterday := streakDates(now, loc)
+
+	cases := []struct {
+		name               string
+		yesterdayCompleted bool
+		wantStreak         int
+		wantResets         int64
+	}{
+		{"kemarin selesai", true, 4, 0},
+		// Reset H-1 harus terjadi sebelum imbalan hari ini, apa pun urutan
+		// goroutine-nya.
+		{"kemarin terlewat", false, 1, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			awardedAt := now.AddDate(0, 0, -1)
+			user := createTestStudent(t, db, func(u *model.User) {
+				u.CurrentStreak = 3
+				u.LongestStreak = 3
+				u.LastStreakAwardedDate = &awardedAt
+			})
+			createTestTask(t, db, user.ID, yesterday, tc.yesterdayCompleted)
+			createTestTask(t, db, user.ID, today, true)
+
+			var fns []func() error
+			for i := 0; i < streakRaceGoroutines; i++ {
+				fns = append(fns,
+					func() error {
+						_, err := engine.AwardToday(user.ID, today, now)
+						return err
+					},
+					func() error {
+						stale := *user
+						_, _, err := engine.evaluateYesterday(&stale, now)
+						return err
+					},
+				)
+			}
+			runConcurrently(t, fns...)
+
+			reloaded := reloadUser(t, db, user.ID)
+			if reloaded.CurrentStreak != tc.wantStreak {
+				t.Errorf("CurrentStreak = %d, want %d", reloaded.CurrentStreak, tc.wantStreak)
+			}
+			if !isLocalDay(reloaded.LastStreakCheckDate, today, loc) {
+				t.Errorf("LastStreakCheckDate = %v, want hari ini", reloaded.LastStreakCheckDate)
+			}
+			if got := countStreakEvents(t, db, user.ID, model.StreakEventAwarded); got != 1 {
+				t.Errorf("jumlah event awarded = %d, want 1", got)
+			}
+			if got := countStreakEvents(t, db, user.ID, model.StreakEventReset); got != tc.wantResets {
+				t.Errorf("jumlah event reset = %d, want %d", got, tc.wantResets)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+)
+
+func mustLoadTimeZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := LoadTimeZone(name)
+	if err != nil {
+		t.Fatalf("LoadTimeZone(%q): %v", name, err)
+	}
+	return loc
+}
+
+func TestStreakDates(t *testing.T) {
+	cases := []struct {
+		name          string
+		zone          string
+		now           time.Time
+		wantToday     string
+		wantYesterday string
+	}{
+		{"jakarta 23:59", "Asia/Jakarta", time.Date(2025, 6, 30, 16, 59, 0, 0, time.UTC), "2025-06-30", "2025-06-29"},
+		{"jakarta 00:00", "Asia/Jakarta", time.Date(2025, 6, 30, 17, 0, 0, 0, time.UTC), "2025-07-01", "2025-06-30"},
+		{"makassar 23:59", "Asia/Makassar", time.Date(2025, 6, 30, 15, 59, 0, 0, time.UTC), "2025-06-30", "2025-06-29"},
+		{"makassar 00:00", "Asia/Makassar", time.Date(2025, 6, 30, 16, 0, 0, 0, time.UTC), "2025-07-01", "2025-06-30"},
+		{"jayapura 23:59", "Asia/Jayapura", time.Date(2025, 6, 30, 14, 59, 0, 0, time.UTC), "2025-06-30", "2025-06-29"},
+		{"jayapura 00:00", "Asia/Jayapura", time.Date(2025, 6, 30, 15, 0, 0, 0, time.UTC), "2025-07-01", "2025-06-30"},
+		{"jakarta 00:00 tahun baru", "Asia/Jakarta", time.Date(2024, 12, 31, 17, 0, 0, 0, time.UTC), "2025-01-01", "2024-12-31"},
+		{"jakarta 00:00 kabisat", "Asia/Jakarta", time.Date(2024, 2, 29, 17, 0, 0, 0, time.UTC), "2024-03-01", "2024-02-29"},
+
+		// America/New_York: jam maju 2025-03-09 02:00 EST -> 03:00 EDT,
+		// jam mundur 2025-11-02 02:00 EDT -> 01:00 EST.
+		{"new york sebelum spring forward", "America/New_York", time.Date(2025, 3, 9, 5, 30, 0, 0, time.UTC), "2025-03-09", "2025-03-08"},
+		{"new york setelah spring forward", "America/New_York", time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC), "2025-03-09", "2025-03-08"},
+		{"new york 23:59 hari spring forward", "America/New_York", time.Date(2025, 3, 10, 3, 59, 0, 0, time.UTC), "2025-03-09", "2025-03-08"},
+		{"new york 00:00 setelah spring forward", "America/New_York", time.Date(2025, 3, 10, 4, 0, 0, 0, time.UTC), "2025-03-10", "2025-03-09"},
+		{"new york 01:30 EDT saat fall back", "America/New_York", time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC), "2025-11-02", "2025-11-01"},
+		{"new york 01:30 EST saat fall back", "America/New_York", time.Date(2025, 11, 2, 6, 30, 0, 0, time.UTC), "2025-11-02", "2025-11-01"},
+		{"new york 23:59 hari fall back", "America/New_York", time.Date(2025, 11, 3, 4, 59, 0, 0, time.UTC), "2025-11-02", "2025-11-01"},
+		{"new york 00:00 setelah fall back", "America/New_York", time.Date(2025, 11, 3, 5, 0, 0, 0, time.UTC), "2025-11-03", "2025-11-02"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			today, yesterday := streakDates(tc.now, mustLoadTimeZone(t, tc.zone))
+
+			if got := today.Format("2006-01-02"); got != tc.wantToday {
+				t.Errorf("today = %s, want %s", got, tc.wantToday)
+			}
+			if got := yesterday.Format("2006-01-02"); got != tc.wantYesterday {
+				t.Errorf("yesterday = %s, want %s", got, tc.wantYesterday)
+			}
+			if diff := today.Sub(yesterday); diff != 24*time.Hour {
+				t.Errorf("today - yesterday = %s, want 24h", diff)
+			}
+			if today.Location() != time.UTC || today.Hour() != 0 || today.Minute() != 0 {
+				t.Errorf("today = %s, want midnight UTC", today)
+			}
+		})
+	}
+}
+
+func TestUserToday(t *testing.T) {
+	now := time.Date(2025, 6, 30, 16, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		timeZone string
+		want     string
+	}{
+		{"jakarta masih 30 juni", "Asia/Jakarta", "2025-06-30"},
+		{"jayapura sudah 1 juli", "Asia/Jayapura", "2025-07-01"},
+		{"zona kosong memakai default", "", "2025-06-30"},
+		{"zona tidak valid memakai default", "Mars/Olympus", "2025-06-30"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &model.User{TimeZone: tc.timeZone}
+			if got := userToday(user, now).Format("2006-01-02"); got != tc.want {
+				t.Errorf("userToday = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsLocalDay(t *testing.T) {
+	day := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		zone string
+		t    *time.Time
+		want bool
+	}{
+		{"nil", "Asia/Jakarta", nil, false},
+		// 2025-06-30 18:00 UTC masih 30 Juni di UTC, tapi sudah 1 Juli 01:00 di Jakarta.
+		{"utc 30 juni jatuh di 1 juli jakarta", "Asia/Jakarta", ptrTime(time.Date(2025, 6, 30, 18, 0, 0, 0, time.UTC)), true},
+		{"utc 30 juni 16:59 masih 30 juni jakarta", "Asia/Jakarta", ptrTime(time.Date(2025, 6, 30, 16, 59, 0, 0, time.UTC)), false},
+		{"utc 1 juli 15:00 sudah 2 juli jayapura", "Asia/Jayapura", ptrTime(time.Date(2025, 7, 1, 15, 0, 0, 0, time.UTC)), false},
+		{"utc 1 juli 14:59 masih 1 juli jayapura", "Asia/Jayapura", ptrTime(time.Date(2025, 7, 1, 14, 59, 0, 0, time.UTC)), true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isLocalDay(tc.t, day, mustLoadTimeZone(t, tc.zone)); got != tc.want {
+				t.Errorf("isLocalDay = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
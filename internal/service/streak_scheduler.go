@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+)
+
+const (
+	// streakJobLockKey adalah key advisory lock Postgres untuk job streak.
+	// Nilainya bebas asal tidak dipakai job lain.
+	streakJobLockKey = 0x5354524b
+
+	defaultStreakCheckInterval = 15 * time.Minute
+	defaultStreakBatchSize     = 200
+	streakJobTimeout           = 30 * time.Minute
+)
+
+// StreakScheduler menjalankan evaluasi streak H-1 untuk semua siswa secara
+// berkala. Karena setiap user punya zona waktu sendiri, job berjalan tiap
+// interval (default 15 menit) dan StreakEngine yang memutuskan apakah user
+// tersebut sudah melewati tengah malam lokal dan belum dicek.
+type StreakScheduler struct {
+	jobRepo   *repository.StreakJobRepository
+	userRepo  *repository.UserRepository
+	streak    *StreakEngine
+	batchSize int
+	ctx       context.Context
+}
+
+func NewStreakScheduler(jobRepo *repository.StreakJobRepository, userRepo *repository.UserRepository, streak *StreakEngine, batchSize int) *StreakScheduler {
+	if batchSize <= 0 {
+		batchSize = defaultStreakBatchSize
+	}
+	return &StreakScheduler{jobRepo: jobRepo, userRepo: userRepo, streak: streak, batchSize: batchSize, ctx: context.Background()}
+}
+
+func (s *StreakScheduler) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultStreakCheckInterval
+	}
+	s.ctx = ctx
+
+	go func() {
+		s.run(model.StreakJobTriggerSchedule)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.run(model.StreakJobTriggerSchedule)
+			}
+		}
+	}()
+	log.Printf("[Streak Job] Scheduler berjalan setiap %s.", interval)
+}
+
+// TriggerNow menjalankan job di background atas permintaan admin. Hasilnya
+// bisa dilihat lewat GetRuns.
+func (s *StreakScheduler) TriggerNow() {
+	go s.run(model.StreakJobTriggerManual)
+}
+
+func (s *StreakScheduler) GetRuns(limitQuery string) ([]dto.StreakJobRunResponse, error) {
+	limit := 10
+	if limitQuery != "" {
+		parsed, err := strconv.Atoi(limitQuery)
+		if err != nil || parsed < 1 || parsed > 100 {
+			return nil, errors.New("parameter limit tidak valid")
+		}
+		limit = parsed
+	}
+
+	runs, err := s.jobRepo.FindLatestRuns(limit)
+	if err != nil {
+		return nil, errors.New("gagal mengambil riwayat job streak")
+	}
+
+	responses := []dto.StreakJobRunResponse{}
+	for _, run := range runs {
+		responses = append(responses, streakJobRunToResponse(&run))
+	}
+	return responses, nil
+}
+
+func (s *StreakScheduler) run(trigger string) {
+	ctx, cancel := context.WithTimeout(s.ctx, streakJobTimeout)
+	defer cancel()
+
+	acquired, err := s.jobRepo.WithAdvisoryLock(streakJobLockKey, func() error {
+		return s.evaluateAll(ctx, trigger)
+	})
+	if err != nil {
+		log.Printf("[Streak Job] Gagal menjalankan job: %v", err)
+		return
+	}
+	if acquired {
+		return
+	}
+
+	log.Printf("[Streak Job] Job sedang berjalan di instance lain, dilewati.")
+	if trigger == model.StreakJobTriggerManual {
+		now := time.Now()
+		if _, err := s.jobRepo.CreateRun(&model.StreakJobRun{
+			Trigger:    trigger,
+			Status:     model.StreakJobSkipped,
+			Error:      "job sedang berjalan di instance lain",
+			StartedAt:  now,
+			FinishedAt: &now,
+		}); err != nil {
+			log.Printf("[Streak Job] Gagal mencatat job yang dilewati: %v", err)
+		}
+	}
+}
+
+func (s *StreakScheduler) evaluateAll(ctx context.Context, trigger string) error {
+	run, err := s.jobRepo.CreateRun(&model.StreakJobRun{
+		Trigger:   trigger,
+		Status:    model.StreakJobRunning,
+		StartedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("[Streak Job] Run %d dimulai (%s).", run.ID, trigger)
+
+	var afterID uint
+	var runErr error
+	for runErr == nil {
+		students, err := s.userRepo.FindStudentsAfterID(afterID, s.batchSize)
+		if err != nil {
+			runErr = err
+			break
+		}
+		if len(students) == 0 {
+			break
+		}
+
+		for i := range students {
+			if err := ctx.Err(); err != nil {
+				runErr = err
+				break
+			}
+
+			run.UsersScanned++
+			checked, reset, err := s.streak.evaluateYesterday(&students[i], time.Now())
+			if err != nil {
+				run.UsersFailed++
+				log.Printf("[Streak Job] %v", err)
+				continue
+			}
+			if checked {
+				run.UsersChecked++
+			}
+			if reset {
+				run.UsersReset++
+			}
+		}
+		afterID = students[len(students)-1].ID
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = model.StreakJobSucceeded
+	if runErr != nil {
+		run.Status = model.StreakJobFailed
+		run.Error = runErr.Error()
+	}
+	if err := s.jobRepo.UpdateRun(run); err != nil {
+		log.Printf("[Streak Job] Gagal menyimpan hasil run %d: %v", run.ID, err)
+	}
+
+	log.Printf("[Streak Job] Run %d selesai: %d siswa, %d dicek, %d di-reset, %d gagal.",
+		run.ID, run.UsersScanned, run.UsersChecked, run.UsersReset, run.UsersFailed)
+	return nil
+}
+
+func streakJobRunToResponse(run *model.StreakJobRun) dto.StreakJobRunResponse {
+	return dto.StreakJobRunResponse{
+		ID:           run.ID,
+		Trigger:      run.Trigger,
+		Status:       run.Status,
+		UsersScanned: run.UsersScanned,
+		UsersChecked: run.UsersChecked,
+		UsersReset:   run.UsersReset,
+		UsersFailed:  run.UsersFailed,
+		Error:        run.Error,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+)
+
+// maxTaskRangeDays membatasi rentang query supaya heatmap satu tahun masih
+// muat dalam satu request tanpa membuka query tanpa batas.
+const maxTaskRangeDays = 366
+
+func (s *TaskService) GetTasksByRange(userIDString string, fromQuery string, toQuery string) ([]dto.TaskDayGroup, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	from, to, err := parseTaskRange(fromQuery, toQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	today, err := s.streak.UserToday(uint(userID), time.Now())
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	if err := s.materializeUntilToday(uint(userID), from, to, today); err != nil {
+		return nil, errors.New("gagal menyiapkan task berulang")
+	}
+
+	tasks, err := s.taskRepo.FindTasksByUserIDBetween(uint(userID), from, to)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data task")
+	}
+	upcoming, err := s.upcomingOccurrences(uint(userID), from, to, today, tasks)
+	if err != nil {
+		return nil, errors.New("gagal menyiapkan task berulang")
+	}
+	tasks = append(tasks, upcoming...)
+
+	tasksByDate := map[string][]dto.TaskResponse{}
+	for _, task := range tasks {
+		key := civilDate(task.TaskDate).Format("2006-01-02")
+		tasksByDate[key] = append(tasksByDate[key], taskToResponse(&task))
+	}
+
+	groups := []dto.TaskDayGroup{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		dayTasks := tasksByDate[key]
+		if dayTasks == nil {
+			dayTasks = []dto.TaskResponse{}
+		}
+		groups = append(groups, dto.TaskDayGroup{Date: key, Tasks: dayTasks})
+	}
+
+	return groups, nil
+}
+
+// GetCalendarSummary mengembalikan ringkasan task per hari untuk tampilan
+// kalender dan heatmap. Default-nya bulan berjalan.
+func (s *TaskService) GetCalendarSummary(userIDString string, fromQuery string, toQuery string) (*dto.CalendarSummaryResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	today, err := s.streak.UserToday(uint(userID), time.Now())
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	if fromQuery == "" && toQuery == "" {
+		firstDay := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		fromQuery = firstDay.Format("2006-01-02")
+		toQuery = firstDay.AddDate(0, 1, -1).Format("2006-01-02")
+	}
+
+	from, to, err := parseTaskRange(fromQuery, toQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.materializeUntilToday(uint(userID), from, to, today); err != nil {
+		return nil, errors.New("gagal menyiapkan task berulang")
+	}
+
+	counts, err := s.taskRepo.CountTasksByUserIDBetween(uint(userID), from, to)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data task")
+	}
+
+	var upcoming []model.Task
+	if to.After(today) {
+		futureTasks, err := s.taskRepo.FindTasksByUserIDBetween(uint(userID), today.AddDate(0, 0, 1), to)
+		if err != nil {
+			return nil, errors.New("gagal mengambil data task")
+		}
+		upcoming, err = s.upcomingOccurrences(uint(userID), from, to, today, futureTasks)
+		if err != nil {
+			return nil, errors.New("gagal menyiapkan task berulang")
+		}
+	}
+
+	streakDays, err := s.eventRepo.FindDaysByTypes(uint(userID), from, to, []string{model.StreakEventAwarded, model.StreakEventFreezeUsed})
+	if err != nil {
+		return nil, errors.New("gagal mengambil riwayat streak")
+	}
+
+	response := &dto.CalendarSummaryResponse{
+		From: from.Format("2006-01-02"),
+		To:   to.Format("2006-01-02"),
+		Days: []dto.CalendarDay{},
+	}
+
+	dayIndex := map[string]int{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		dayIndex[key] = len(response.Days)
+		response.Days = append(response.Days, dto.CalendarDay{Date: key})
+	}
+
+	for _, count := range counts {
+		i, ok := dayIndex[civilDate(count.TaskDate).Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+
+		day := &response.Days[i]
+		day.Total += count.Total
+		day.Completed += count.Completed
+		response.TotalTasks += count.Total
+		response.CompletedTasks += count.Completed
+	}
+
+	for _, task := range upcoming {
+		if i, ok := dayIndex[civilDate(task.TaskDate).Format("2006-01-02")]; ok {
+			response.Days[i].Total++
+			response.TotalTasks++
+		}
+	}
+
+	// Hari dihitung ke streak sesuai ledger StreakEvent: hari yang diberi
+	// streak atau ditutup freeze, bukan sekadar hari yang semua task-nya
+	// selesai.
+	for _, streakDay := range streakDays {
+		if i, ok := dayIndex[civilDate(streakDay).Format("2006-01-02")]; ok {
+			response.Days[i].CountedTowardStreak = true
+			response.StreakDays++
+		}
+	}
+
+	return response, nil
+}
+
+// materializeUntilToday hanya menyimpan occurrence sampai hari ini (tanggal
+// lokal user). Hari setelahnya dihitung lewat upcomingOccurrences supaya
+// membuka kalender bulan depan tidak membuat baris task.
+func (s *TaskService) materializeUntilToday(userID uint, from, to, today time.Time) error {
+	if from.After(today) {
+		return nil
+	}
+	if to.After(today) {
+		to = today
+	}
+	return s.materializeOccurrences(userID, from, to)
+}
+
+// upcomingOccurrences menghitung occurrence template berulang setelah hari ini
+// di rentang [from, to] tanpa menyimpannya. Occurrence yang sudah ada di
+// existing (misalnya dibuat sebelum aturan ini) tidak diulang. Task hasilnya
+// belum punya ID.
+func (s *TaskService) upcomingOccurrences(userID uint, from, to, today time.Time, existing []model.Task) ([]model.Task, error) {
+	start := today.AddDate(0, 0, 1)
+	if from.After(start) {
+		start = from
+	}
+	if start.After(to) {
+		return nil, nil
+	}
+
+	recurrences, err := s.recurrenceRepo.FindActiveBetween(userID, start, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := map[string]bool{}
+	for _, task := range existing {
+		if task.RecurrenceID != nil {
+			stored[occurrenceKey(*task.RecurrenceID, task.TaskDate)] = true
+		}
+	}
+
+	var upcoming []model.Task
+	for _, occurrence := range projectRecurrences(recurrences, userID, start, to) {
+		if !stored[occurrenceKey(*occurrence.RecurrenceID, occurrence.TaskDate)] {
+			upcoming = append(upcoming, occurrence)
+		}
+	}
+	return upcoming, nil
+}
+
+func occurrenceKey(recurrenceID uint, day time.Time) string {
+	return fmt.Sprintf("%d:%s", recurrenceID, civilDate(day).Format("2006-01-02"))
+}
+
+func parseTaskRange(fromQuery string, toQuery string) (time.Time, time.Time, error) {
+	if fromQuery == "" || toQuery == "" {
+		return time.Time{}, time.Time{}, errors.New("parameter from dan to wajib diisi bersamaan")
+	}
+
+	from, err := time.Parse("2006-01-02", fromQuery)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+	}
+	to, err := time.Parse("2006-01-02", toQuery)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("tanggal akhir harus setelah tanggal awal")
+	}
+	if daysBetween(from, to) >= maxTaskRangeDays {
+		return time.Time{}, time.Time{}, errors.New("rentang tanggal maksimal 366 hari")
+	}
+
+	return from, to, nil
+}
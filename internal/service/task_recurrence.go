@@ -0,0 +1,297 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+)
+
+const (
+	TaskScopeThis   = "this"
+	TaskScopeFuture = "future"
+)
+
+func (s *TaskService) CreateRecurringTask(req dto.CreateRecurringTaskRequest, userIDString string) (*dto.TaskRecurrenceResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+	}
+
+	recurrence := &model.TaskRecurrence{
+		UserID:    uint(userID),
+		Title:     req.Title,
+		Frequency: req.Frequency,
+		Interval:  1,
+		StartDate: startDate,
+		Count:     req.Count,
+	}
+
+	switch req.Frequency {
+	case model.RecurrenceWeekly:
+		if len(req.Weekdays) == 0 {
+			return nil, errors.New("weekdays wajib diisi untuk frekuensi weekly")
+		}
+		for _, weekday := range req.Weekdays {
+			if !slices.Contains(recurrence.Weekdays, weekday) {
+				recurrence.Weekdays = append(recurrence.Weekdays, weekday)
+			}
+		}
+		slices.Sort(recurrence.Weekdays)
+	case model.RecurrenceInterval:
+		if req.Interval == 0 {
+			return nil, errors.New("interval wajib diisi untuk frekuensi interval")
+		}
+		recurrence.Interval = req.Interval
+	}
+
+	if req.UntilDate != "" {
+		untilDate, err := time.Parse("2006-01-02", req.UntilDate)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+		}
+		if untilDate.Before(startDate) {
+			return nil, errors.New("until_date tidak boleh sebelum start_date")
+		}
+		recurrence.UntilDate = &untilDate
+	}
+
+	createdRecurrence, err := s.recurrenceRepo.Create(recurrence)
+	if err != nil {
+		return nil, errors.New("gagal menyimpan task berulang ke database")
+	}
+
+	log.Printf("[Task] User %d membuat task berulang %d (%s).", userID, createdRecurrence.ID, createdRecurrence.Frequency)
+	response := recurrenceToResponse(createdRecurrence)
+	return &response, nil
+}
+
+func (s *TaskService) GetRecurringTasks(userIDString string) ([]dto.TaskRecurrenceResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	recurrences, err := s.recurrenceRepo.FindByUserID(uint(userID))
+	if err != nil {
+		return nil, errors.New("gagal mengambil data task berulang")
+	}
+
+	responses := []dto.TaskRecurrenceResponse{}
+	for _, recurrence := range recurrences {
+		responses = append(responses, recurrenceToResponse(&recurrence))
+	}
+	return responses, nil
+}
+
+// materializeOccurrences membuat baris task untuk setiap template berulang
+// yang jatuh di rentang [from, to].
+func (s *TaskService) materializeOccurrences(userID uint, from, to time.Time) error {
+	return materializeRecurrences(s.taskRepo, s.recurrenceRepo, userID, from, to)
+}
+
+// materializeRecurrences dipakai bersama oleh TaskService dan StreakEngine.
+// Occurrence yang sudah ada tidak disentuh, jadi perubahan "hanya occurrence
+// ini" tetap bertahan.
+func materializeRecurrences(taskRepo *repository.TaskRepository, recurrenceRepo *repository.TaskRecurrenceRepository, userID uint, from, to time.Time) error {
+	from, to = civilDate(from), civilDate(to)
+
+	recurrences, err := recurrenceRepo.FindActiveBetween(userID, from, to)
+	if err != nil {
+		return err
+	}
+
+	return taskRepo.CreateOccurrences(projectRecurrences(recurrences, userID, from, to))
+}
+
+// projectRecurrences menghitung occurrence setiap template di rentang
+// [from, to] tanpa menyimpannya.
+func projectRecurrences(recurrences []model.TaskRecurrence, userID uint, from, to time.Time) []model.Task {
+	var occurrences []model.Task
+	for _, recurrence := range recurrences {
+		recurrenceID := recurrence.ID
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			if !recurrenceOccursOn(&recurrence, day) {
+				continue
+			}
+			occurrences = append(occurrences, model.Task{
+				Title:        recurrence.Title,
+				TaskDate:     day,
+				UserID:       userID,
+				RecurrenceID: &recurrenceID,
+			})
+		}
+	}
+	return occurrences
+}
+
+// splitRecurrence memotong template di tanggal from: template lama berakhir
+// sehari sebelumnya dan template baru (dengan judul baru) berlaku mulai from.
+// Occurrence yang sudah dibuat mulai from ikut dipindah ke template baru.
+func (s *TaskService) splitRecurrence(recurrence *model.TaskRecurrence, from time.Time, title string) (uint, error) {
+	from = civilDate(from)
+	start := civilDate(recurrence.StartDate)
+
+	if !from.After(start) {
+		recurrence.Title = title
+		if _, err := s.recurrenceRepo.Update(recurrence); err != nil {
+			return 0, err
+		}
+		return recurrence.ID, s.taskRepo.UpdateRecurrenceFrom(recurrence.ID, from, recurrence.ID, title)
+	}
+
+	next := &model.TaskRecurrence{
+		UserID:    recurrence.UserID,
+		Title:     title,
+		Frequency: recurrence.Frequency,
+		Interval:  recurrence.Interval,
+		Weekdays:  recurrence.Weekdays,
+		StartDate: from,
+		UntilDate: recurrence.UntilDate,
+	}
+	if recurrence.Count != nil {
+		remaining := *recurrence.Count - recurrenceOccurrencesBefore(recurrence, start, from)
+		next.Count = &remaining
+	}
+	for _, excluded := range recurrence.ExcludedDates {
+		if excluded >= from.Format("2006-01-02") {
+			next.ExcludedDates = append(next.ExcludedDates, excluded)
+		}
+	}
+	if _, err := s.recurrenceRepo.Create(next); err != nil {
+		return 0, err
+	}
+
+	until := from.AddDate(0, 0, -1)
+	recurrence.UntilDate = &until
+	if _, err := s.recurrenceRepo.Update(recurrence); err != nil {
+		return 0, err
+	}
+
+	return next.ID, s.taskRepo.UpdateRecurrenceFrom(recurrence.ID, from, next.ID, title)
+}
+
+// endRecurrence menghentikan template mulai tanggal from dan menghapus
+// occurrence yang sudah dibuat sejak tanggal itu.
+func (s *TaskService) endRecurrence(recurrence *model.TaskRecurrence, from time.Time) error {
+	from = civilDate(from)
+
+	if err := s.taskRepo.DeleteByRecurrenceFrom(recurrence.ID, from); err != nil {
+		return err
+	}
+
+	if !from.After(civilDate(recurrence.StartDate)) {
+		return s.recurrenceRepo.Delete(recurrence.ID)
+	}
+
+	until := from.AddDate(0, 0, -1)
+	recurrence.UntilDate = &until
+	_, err := s.recurrenceRepo.Update(recurrence)
+	return err
+}
+
+func (s *TaskService) excludeOccurrence(recurrence *model.TaskRecurrence, date time.Time) error {
+	key := civilDate(date).Format("2006-01-02")
+	if slices.Contains(recurrence.ExcludedDates, key) {
+		return nil
+	}
+	recurrence.ExcludedDates = append(recurrence.ExcludedDates, key)
+	_, err := s.recurrenceRepo.Update(recurrence)
+	return err
+}
+
+func recurrenceOccursOn(recurrence *model.TaskRecurrence, day time.Time) bool {
+	day = civilDate(day)
+	start := civilDate(recurrence.StartDate)
+
+	if day.Before(start) {
+		return false
+	}
+	if recurrence.UntilDate != nil && day.After(civilDate(*recurrence.UntilDate)) {
+		return false
+	}
+	if slices.Contains(recurrence.ExcludedDates, day.Format("2006-01-02")) {
+		return false
+	}
+	if !recurrenceMatches(recurrence, start, day) {
+		return false
+	}
+	if recurrence.Count != nil && recurrenceOccurrencesBefore(recurrence, start, day) >= *recurrence.Count {
+		return false
+	}
+	return true
+}
+
+func recurrenceMatches(recurrence *model.TaskRecurrence, start, day time.Time) bool {
+	switch recurrence.Frequency {
+	case model.RecurrenceDaily:
+		return true
+	case model.RecurrenceWeekly:
+		return slices.Contains(recurrence.Weekdays, int(day.Weekday()))
+	case model.RecurrenceInterval:
+		return daysBetween(start, day)%max(recurrence.Interval, 1) == 0
+	}
+	return false
+}
+
+// recurrenceOccurrencesBefore menghitung jumlah occurrence terjadwal dari
+// start sampai sebelum day. Tanggal yang dikecualikan tetap dihitung, sama
+// seperti EXDATE di RRULE.
+func recurrenceOccurrencesBefore(recurrence *model.TaskRecurrence, start, day time.Time) int {
+	days := daysBetween(start, day)
+	if days <= 0 {
+		return 0
+	}
+
+	switch recurrence.Frequency {
+	case model.RecurrenceDaily:
+		return days
+	case model.RecurrenceInterval:
+		interval := max(recurrence.Interval, 1)
+		return (days + interval - 1) / interval
+	case model.RecurrenceWeekly:
+		weeks := days / 7
+		count := weeks * len(recurrence.Weekdays)
+		for d := start.AddDate(0, 0, weeks*7); d.Before(day); d = d.AddDate(0, 0, 1) {
+			if slices.Contains(recurrence.Weekdays, int(d.Weekday())) {
+				count++
+			}
+		}
+		return count
+	}
+	return 0
+}
+
+// civilDate membuang komponen jam dan zona waktu sehingga dua tanggal bisa
+// dibandingkan sebagai tanggal kalender.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(start, end time.Time) int {
+	return int(civilDate(end).Sub(civilDate(start)).Hours() / 24)
+}
+
+func recurrenceToResponse(recurrence *model.TaskRecurrence) dto.TaskRecurrenceResponse {
+	return dto.TaskRecurrenceResponse{
+		ID:            recurrence.ID,
+		Title:         recurrence.Title,
+		Frequency:     recurrence.Frequency,
+		Interval:      recurrence.Interval,
+		Weekdays:      recurrence.Weekdays,
+		StartDate:     recurrence.StartDate,
+		UntilDate:     recurrence.UntilDate,
+		Count:         recurrence.Count,
+		ExcludedDates: recurrence.ExcludedDates,
+	}
+}
@@ -13,12 +13,17 @@ import (
 )
 
 type TaskService struct {
-	taskRepo *repository.TaskRepository
-	userRepo *repository.UserRepository
+	taskRepo       *repository.TaskRepository
+	userRepo       *repository.UserRepository
+	recurrenceRepo *repository.TaskRecurrenceRepository
+	eventRepo      *repository.StreakEventRepository
+	streak         *StreakEngine
+	achievements   *AchievementService
+	xp             *XPService
 }
 
-func NewTaskService(taskRepo *repository.TaskRepository, userRepo *repository.UserRepository) *TaskService {
-	return &TaskService{taskRepo: taskRepo, userRepo: userRepo}
+func NewTaskService(taskRepo *repository.TaskRepository, userRepo *repository.UserRepository, recurrenceRepo *repository.TaskRecurrenceRepository, eventRepo *repository.StreakEventRepository, streak *StreakEngine, achievements *AchievementService, xp *XPService) *TaskService {
+	return &TaskService{taskRepo: taskRepo, userRepo: userRepo, recurrenceRepo: recurrenceRepo, eventRepo: eventRepo, streak: streak, achievements: achievements, xp: xp}
 }
 
 func (s *TaskService) CreateTask(req dto.CreateTaskRequest, userIDString string) (*dto.TaskResponse, error) {
@@ -55,10 +60,17 @@ func (s *TaskService) CreateTask(req dto.CreateTaskRequest, userIDString string)
 	return response, nil
 }
 
-func (s *TaskService) UpdateTask(taskIDString string, userIDString string, req dto.UpdateTaskRequest) (*dto.TaskResponse, error) {
+// UpdateTask mengubah satu task. Untuk task berulang dengan scope "future",
+// judul baru juga berlaku untuk semua occurrence berikutnya; status selesai
+// tetap hanya untuk occurrence ini.
+func (s *TaskService) UpdateTask(taskIDString string, userIDString string, scope string, req dto.UpdateTaskRequest) (*dto.TaskResponse, error) {
 	userID, _ := strconv.ParseUint(userIDString, 10, 32)
 	taskID, _ := strconv.ParseUint(taskIDString, 10, 32)
 
+	if err := validateTaskScope(scope); err != nil {
+		return nil, err
+	}
+
 	task, err := s.taskRepo.FindByID(uint(taskID))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -71,74 +83,48 @@ func (s *TaskService) UpdateTask(taskIDString string, userIDString string, req d
 		return nil, errors.New("akses ditolak: anda bukan pemilik task ini")
 	}
 
-	task.Title = req.Title
+	if scope == TaskScopeFuture && task.RecurrenceID != nil {
+		recurrence, err := s.recurrenceRepo.FindByID(*task.RecurrenceID, uint(userID))
+		if err != nil {
+			return nil, errors.New("gagal mengambil data task berulang")
+		}
+		recurrenceID, err := s.splitRecurrence(recurrence, task.TaskDate, req.Title)
+		if err != nil {
+			return nil, errors.New("gagal mengupdate task berulang")
+		}
+		task.RecurrenceID = &recurrenceID
+	}
+
+	if task.AssignedByID == nil {
+		task.Title = req.Title
+	}
 	task.IsCompleted = req.IsCompleted
 	updatedTask, err := s.taskRepo.Update(task)
 	if err != nil {
 		return nil, errors.New("gagal mengupdate task")
 	}
 
-	if req.IsCompleted {
-		go s.checkRealtimeStreak(uint(userID), task.TaskDate)
-	}
-
 	response := taskToResponse(updatedTask)
-	return &response, nil
-}
-
-func (s *TaskService) checkRealtimeStreak(userID uint, taskDate time.Time) {
-	log.Printf("[Streak H-0] User %d menyelesaikan task. Memeriksa...", userID)
 
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
-
-	taskDay := time.Date(taskDate.Year(), taskDate.Month(), taskDate.Day(), 0, 0, 0, 0, time.Local)
-	if !taskDay.Equal(today) {
-		log.Printf("[Streak H-0] User %d menyelesaikan task lama. Streak H-0 diabaikan.", userID)
-		return
-	}
-
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return
-	}
-
-	if user.LastStreakAwardedDate != nil {
-		lastAward := *user.LastStreakAwardedDate
-		lastAwardDate := time.Date(lastAward.Year(), lastAward.Month(), lastAward.Day(), 0, 0, 0, 0, time.Local)
-		if lastAwardDate.Equal(today) {
-			log.Printf("[Streak H-0] User %d sudah dapat imbalan hari ini. Diabaikan.", userID)
-			return
-		}
-	}
-
-	tasksToday, err := s.taskRepo.FindTasksByUserIDAndDate(userID, today)
-	if err != nil {
-		return
-	}
+	if req.IsCompleted {
+		s.xp.GrantTaskCompleted(uint(userID), updatedTask.ID)
 
-	totalTasks := len(tasksToday)
-	completedTasks := 0
-	for _, t := range tasksToday {
-		if t.IsCompleted {
-			completedTasks++
+		streak, err := s.streak.AwardToday(uint(userID), task.TaskDate, time.Now())
+		if err != nil {
+			// Task sudah tersimpan; kegagalan streak tidak membatalkan update.
+			log.Printf("[Streak H-0] Gagal memeriksa streak user %d: %v", userID, err)
 		}
+		response.Streak = streak
+		s.achievements.Publish(uint(userID), AchievementEventTaskCompleted)
 	}
 
-	if totalTasks > 0 && totalTasks == completedTasks {
-		user.CurrentStreak += 1
-		user.LastStreakAwardedDate = &now
-
-		_, err := s.userRepo.Update(user)
-		if err == nil {
-			log.Printf("[Streak H-0] SUKSES! User %d menyelesaikan semua task H-0. Streak naik ke %d.", userID, user.CurrentStreak)
-		}
-	} else {
-		log.Printf("[Streak H-0] User %d belum selesai. (Total: %d, Selesai: %d)", userID, totalTasks, completedTasks)
-	}
+	return &response, nil
 }
 
-func (s *TaskService) DeleteTask(taskIDString string, userIDString string) error {
+// DeleteTask menghapus satu task. Untuk task berulang, scope "this" hanya
+// melewati occurrence ini, sedangkan "future" menghentikan template mulai
+// tanggal task tersebut.
+func (s *TaskService) DeleteTask(taskIDString string, userIDString string, scope string) error {
 	userID, err := strconv.ParseUint(userIDString, 10, 32)
 	if err != nil {
 		return errors.New("user ID tidak valid")
@@ -147,6 +133,9 @@ func (s *TaskService) DeleteTask(taskIDString string, userIDString string) error
 	if err != nil {
 		return errors.New("task ID tidak valid")
 	}
+	if err := validateTaskScope(scope); err != nil {
+		return err
+	}
 
 	task, err := s.taskRepo.FindByID(uint(taskID))
 	if err != nil {
@@ -160,6 +149,28 @@ func (s *TaskService) DeleteTask(taskIDString string, userIDString string) error
 		return errors.New("akses ditolak: anda bukan pemilik task ini") // 403
 	}
 
+	if task.AssignedByID != nil {
+		return errors.New("akses ditolak: task dari pembimbing tidak bisa dihapus")
+	}
+
+	if task.RecurrenceID != nil {
+		recurrence, err := s.recurrenceRepo.FindByID(*task.RecurrenceID, uint(userID))
+		if err != nil {
+			return errors.New("gagal mengambil data task berulang")
+		}
+
+		if scope == TaskScopeFuture {
+			if err := s.endRecurrence(recurrence, task.TaskDate); err != nil {
+				return errors.New("gagal menghapus task berulang")
+			}
+			return nil
+		}
+
+		if err := s.excludeOccurrence(recurrence, task.TaskDate); err != nil {
+			return errors.New("gagal menghapus task berulang")
+		}
+	}
+
 	err = s.taskRepo.Delete(uint(taskID))
 	if err != nil {
 		return errors.New("gagal menghapus task")
@@ -177,8 +188,10 @@ func (s *TaskService) GetTasks(userIDString string, dateQuery string) ([]dto.Tas
 	var targetDate time.Time
 
 	if dateQuery == "" {
-		now := time.Now()
-		targetDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		targetDate, err = s.streak.UserToday(uint(userID), time.Now())
+		if err != nil {
+			return nil, errors.New("user tidak ditemukan")
+		}
 	} else {
 		parsedDate, err := time.Parse("2006-01-02", dateQuery)
 		if err != nil {
@@ -187,6 +200,10 @@ func (s *TaskService) GetTasks(userIDString string, dateQuery string) ([]dto.Tas
 		targetDate = parsedDate
 	}
 
+	if err := s.materializeOccurrences(uint(userID), targetDate, targetDate); err != nil {
+		return nil, errors.New("gagal menyiapkan task berulang")
+	}
+
 	tasks, err := s.taskRepo.FindTasksByUserIDAndDate(uint(userID), targetDate)
 	if err != nil {
 		return nil, errors.New("gagal mengambil data task")
@@ -202,10 +219,20 @@ func (s *TaskService) GetTasks(userIDString string, dateQuery string) ([]dto.Tas
 
 func taskToResponse(task *model.Task) dto.TaskResponse {
 	return dto.TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		IsCompleted: task.IsCompleted,
-		TaskDate:    task.TaskDate,
-		UserID:      task.UserID,
+		ID:           task.ID,
+		Title:        task.Title,
+		IsCompleted:  task.IsCompleted,
+		TaskDate:     task.TaskDate,
+		UserID:       task.UserID,
+		AssignedByID: task.AssignedByID,
+		RecurrenceID: task.RecurrenceID,
+	}
+}
+
+func validateTaskScope(scope string) error {
+	switch scope {
+	case "", TaskScopeThis, TaskScopeFuture:
+		return nil
 	}
+	return errors.New("scope harus this atau future")
 }
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var testUserSeq atomic.Int64
+
+// openTestDB membuka database Postgres dari TEST_DATABASE_URL dan
+// menjalankan AutoMigrate. Test dilewati jika variabel itu kosong. Setiap
+// test membuat user sendiri, jadi database boleh dipakai bersama.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL belum diisi, test database dilewati")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("gagal terhubung ke database test: %v", err)
+	}
+
+	err = db.AutoMigrate(&model.User{}, &model.Task{}, &model.Material{}, &model.Quiz{}, &model.QuizQuestion{}, &model.QuizAttempt{}, &model.MaterialSummary{}, &model.MaterialChunk{}, &model.IngestionJob{}, &model.RefreshToken{}, &model.FocusSession{}, &model.Room{}, &model.TaskRecurrence{}, &model.StreakJobRun{}, &model.StreakEvent{}, &model.Badge{}, &model.UserBadge{}, &model.XPEvent{}, &model.FlashcardDeck{}, &model.Flashcard{}, &model.MaterialPassage{}, &model.MaterialConversation{}, &model.MaterialChatMessage{})
+	if err != nil {
+		t.Fatalf("gagal migrasi database test: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// createTestStudent membuat siswa baru dengan email unik. modify boleh nil.
+func createTestStudent(t *testing.T, db *gorm.DB, modify func(user *model.User)) *model.User {
+	t.Helper()
+
+	user := &model.User{
+		Username:     "siswa test",
+		Email:        fmt.Sprintf("siswa-%d-%d@test.local", time.Now().UnixNano(), testUserSeq.Add(1)),
+		PasswordHash: "-",
+		Role:         "siswa",
+		TimeZone:     DefaultTimeZone,
+	}
+	if modify != nil {
+		modify(user)
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("gagal membuat user test: %v", err)
+	}
+	return user
+}
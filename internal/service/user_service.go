@@ -2,7 +2,6 @@ package service
 
 import (
 	"errors"
-	"fmt"
 	"log"
 	"strconv"
 	"time"
@@ -13,13 +12,19 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	defaultStreakHistoryLimit = 30
+	maxStreakHistoryLimit     = 100
+)
+
 type UserService struct {
-	userRepo *repository.UserRepository
-	taskRepo *repository.TaskRepository
+	userRepo        *repository.UserRepository
+	streakEventRepo *repository.StreakEventRepository
+	streak          *StreakEngine
 }
 
-func NewUserService(userRepo *repository.UserRepository, taskRepo *repository.TaskRepository) *UserService {
-	return &UserService{userRepo: userRepo, taskRepo: taskRepo}
+func NewUserService(userRepo *repository.UserRepository, streakEventRepo *repository.StreakEventRepository, streak *StreakEngine) *UserService {
+	return &UserService{userRepo: userRepo, streakEventRepo: streakEventRepo, streak: streak}
 }
 
 func (s *UserService) GetAllUsers() ([]dto.UserResponse, error) {
@@ -76,16 +81,30 @@ func (s *UserService) UpdateUser(id uint, req dto.UpdateUserRequest) (*dto.UserR
 		}
 	}
 
+	roleChanged := user.Role != req.Role
+
 	user.Username = req.Username
 	user.Email = req.Email
 	user.Role = req.Role
 
-	updatedUser, err := s.userRepo.Update(user)
+	err = s.userRepo.UpdateColumns(user.ID, map[string]interface{}{
+		"username": user.Username,
+		"email":    user.Email,
+		"role":     user.Role,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	response := userToResponse(updatedUser)
+	// Access token lama membawa role lama, jadi dicabut lewat increment
+	// atomik supaya tidak bertabrakan dengan logout semua perangkat.
+	if roleChanged {
+		if err := s.userRepo.IncrementTokenVersion(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	response := userToResponse(user)
 	return &response, nil
 }
 
@@ -111,67 +130,114 @@ func (s *UserService) CheckAndUpdateStreak(userIDString string) (*model.User, er
 		return nil, errors.New("user tidak ditemukan")
 	}
 
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	return s.streak.CheckYesterday(user, time.Now())
+}
+
+func (s *UserService) GetStreakHistory(userIDString string, pageQuery string, limitQuery string) (*dto.StreakHistoryResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
 
-	if user.LastStreakCheckDate != nil {
-		lastCheck := *user.LastStreakCheckDate
-		lastCheckDate := time.Date(lastCheck.Year(), lastCheck.Month(), lastCheck.Day(), 0, 0, 0, 0, time.Local)
+	page := 1
+	if pageQuery != "" {
+		page, err = strconv.Atoi(pageQuery)
+		if err != nil || page < 1 {
+			return nil, errors.New("parameter page tidak valid")
+		}
+	}
 
-		if lastCheckDate.Equal(today) {
-			log.Printf("[Streak H-1] User %d sudah dicek hari ini. Tidak ada update.", userID)
-			return user, nil
+	limit := defaultStreakHistoryLimit
+	if limitQuery != "" {
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil || limit < 1 {
+			return nil, errors.New("parameter limit tidak valid")
+		}
+		if limit > maxStreakHistoryLimit {
+			limit = maxStreakHistoryLimit
 		}
 	}
 
-	yesterday := today.Add(-24 * time.Hour)
-	log.Printf("[Streak H-1] User %d belum dicek. Mengecek tugas H-1 (%s)...", userID, yesterday.Format("2006-01-02"))
+	user, err := s.userRepo.FindByID(uint(userID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
 
-	tasks, err := s.taskRepo.FindTasksByUserIDAndDate(user.ID, yesterday)
+	events, total, err := s.streakEventRepo.FindByUserID(user.ID, limit, (page-1)*limit)
 	if err != nil {
-		return nil, fmt.Errorf("gagal ambil task user %d: %w", user.ID, err)
+		return nil, errors.New("gagal mengambil riwayat streak")
 	}
 
-	totalTasks := len(tasks)
-	completedTasks := 0
-	for _, task := range tasks {
-		if task.IsCompleted {
-			completedTasks++
-		}
+	items := []dto.StreakEventResponse{}
+	for _, event := range events {
+		items = append(items, dto.StreakEventResponse{
+			ID:           event.ID,
+			Type:         event.Type,
+			Day:          event.Day.Format("2006-01-02"),
+			StreakBefore: event.StreakBefore,
+			StreakAfter:  event.StreakAfter,
+			FreezesAfter: event.FreezesAfter,
+			CreatedAt:    event.CreatedAt,
+		})
 	}
 
-	streakUpdated := false
-	if totalTasks == 0 {
-		if user.CurrentStreak > 0 {
-			log.Printf("[Streak H-1] User %d: 0 tugas H-1. Streak reset ke 0.", user.ID)
-			user.CurrentStreak = 0
-			streakUpdated = true
-		}
-	} else if totalTasks > 0 && completedTasks != totalTasks {
-		if user.CurrentStreak > 0 {
-			log.Printf("[Streak H-1] User %d: Tugas H-1 tidak selesai. Streak reset ke 0.", user.ID)
-			user.CurrentStreak = 0
-			streakUpdated = true
-		}
-	} else {
-		log.Printf("[Streak H-1] User %d: Sukses H-1. Tidak ada perubahan (imbalan sudah diberikan H-0).", user.ID)
+	return &dto.StreakHistoryResponse{
+		CurrentStreak: user.CurrentStreak,
+		LongestStreak: max(user.LongestStreak, user.CurrentStreak),
+		StreakFreezes: user.StreakFreezes,
+		Events:        items,
+		Page:          page,
+		Limit:         limit,
+		Total:         total,
+	}, nil
+}
+
+// UpdateTimeZone mengganti zona waktu user. Perhitungan streak berikutnya
+// memakai zona yang baru.
+func (s *UserService) UpdateTimeZone(userIDString string, req dto.UpdateTimeZoneRequest) (*dto.UserResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	if _, err := LoadTimeZone(req.TimeZone); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(uint(userID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	user.TimeZone = req.TimeZone
+	if err := s.userRepo.UpdateColumns(user.ID, map[string]interface{}{"time_zone": user.TimeZone}); err != nil {
+		return nil, errors.New("gagal mengupdate zona waktu")
 	}
-	// ------------------------------------------
 
-	user.LastStreakCheckDate = &now
+	response := userToResponse(user)
+	return &response, nil
+}
 
-	_, err = s.userRepo.Update(user)
+// UpdateLeaderboardVisibility mengatur opt-out user dari leaderboard. XP tetap
+// dicatat dan bisa dilihat sendiri lewat /users/me/xp.
+func (s *UserService) UpdateLeaderboardVisibility(userIDString string, req dto.UpdateLeaderboardVisibilityRequest) (*dto.UserResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
 	if err != nil {
-		return nil, fmt.Errorf("gagal update streak user %d: %w", user.ID, err)
+		return nil, errors.New("user ID tidak valid")
 	}
 
-	if streakUpdated {
-		log.Printf("[Streak H-1] User %d berhasil di-reset.", user.ID)
-	} else {
-		log.Printf("[Streak H-1] Pengecekan User %d selesai, tidak ada reset.", user.ID)
+	user, err := s.userRepo.FindByID(uint(userID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	user.HideFromLeaderboard = *req.HideFromLeaderboard
+	if err := s.userRepo.UpdateColumns(user.ID, map[string]interface{}{"hide_from_leaderboard": user.HideFromLeaderboard}); err != nil {
+		return nil, errors.New("gagal mengupdate pengaturan leaderboard")
 	}
 
-	return user, nil
+	response := userToResponse(user)
+	return &response, nil
 }
 
 func userToResponse(user *model.User) dto.UserResponse {
@@ -181,6 +247,11 @@ func userToResponse(user *model.User) dto.UserResponse {
 		Email:         user.Email,
 		Role:          user.Role,
 		CurrentStreak: user.CurrentStreak,
+		LongestStreak: max(user.LongestStreak, user.CurrentStreak),
+		StreakFreezes: user.StreakFreezes,
+		TimeZone:      user.TimeZone,
 		CreatedAt:     user.CreatedAt,
+
+		HideFromLeaderboard: user.HideFromLeaderboard,
 	}
 }
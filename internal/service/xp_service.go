@@ -0,0 +1,251 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mohamadarif03/focus-room-be/internal/dto"
+	"github.com/mohamadarif03/focus-room-be/internal/model"
+	"github.com/mohamadarif03/focus-room-be/internal/repository"
+)
+
+const (
+	xpPerTask       = 10
+	xpPerStreakDay  = 20
+	xpPerQuizPoints = 5 // 1 XP untuk setiap 5 poin skor kuis (maksimal 20 XP)
+	xpLevelStep     = 100
+
+	LeaderboardWeekly  = "weekly"
+	LeaderboardAllTime = "all_time"
+
+	LeaderboardScopeGlobal = "global"
+	LeaderboardScopeMentor = "mentor"
+	LeaderboardScopeRoom   = "room"
+
+	defaultLeaderboardLimit = 20
+	maxLeaderboardLimit     = 100
+)
+
+// XPService mencatat XP ke ledger dan menyusun leaderboard. Setiap sumber XP
+// punya Ref unik, jadi menyelesaikan ulang task yang sama tidak menambah XP.
+type XPService struct {
+	xpRepo   *repository.XPRepository
+	userRepo *repository.UserRepository
+	rooms    *RoomService
+}
+
+func NewXPService(xpRepo *repository.XPRepository, userRepo *repository.UserRepository, rooms *RoomService) *XPService {
+	return &XPService{xpRepo: xpRepo, userRepo: userRepo, rooms: rooms}
+}
+
+func (s *XPService) GrantTaskCompleted(userID, taskID uint) {
+	s.grant(userID, model.XPSourceTaskCompleted, fmt.Sprintf("task:%d", taskID), xpPerTask)
+}
+
+// GrantStreakDay memberi XP untuk hari streak. day adalah tanggal lokal user.
+func (s *XPService) GrantStreakDay(userID uint, day time.Time) {
+	s.grant(userID, model.XPSourceStreakDay, "streak:"+day.Format("2006-01-02"), xpPerStreakDay)
+}
+
+// GrantQuizScore memberi XP sekali per kuis. Ref memakai ID kuis, sehingga
+// hanya attempt pertama yang dikumpulkan yang dihitung; mengulang kuis yang
+// kunci jawabannya sudah terlihat tidak menambah XP.
+func (s *XPService) GrantQuizScore(userID, quizID uint, score int) {
+	s.grant(userID, model.XPSourceQuizScore, fmt.Sprintf("quiz:%d", quizID), score/xpPerQuizPoints)
+}
+
+func (s *XPService) grant(userID uint, source string, ref string, amount int) {
+	if amount <= 0 {
+		return
+	}
+
+	granted, err := s.xpRepo.Grant(&model.XPEvent{UserID: userID, Source: source, Ref: ref, Amount: amount})
+	if err != nil {
+		log.Printf("[XP] Gagal mencatat XP %s user %d: %v", ref, userID, err)
+		return
+	}
+	if granted {
+		log.Printf("[XP] User %d mendapat %d XP dari %s.", userID, amount, ref)
+	}
+}
+
+func (s *XPService) GetMyXP(userIDString string) (*dto.XPSummaryResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	user, err := s.userRepo.FindByID(uint(userID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	totalXP, err := s.xpRepo.SumByUserID(user.ID, nil)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data XP")
+	}
+	weekStart := startOfWeek(time.Now(), userLocation(user))
+	weeklyXP, err := s.xpRepo.SumByUserID(user.ID, &weekStart)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data XP")
+	}
+
+	level := levelForXP(totalXP)
+	return &dto.XPSummaryResponse{
+		TotalXP:             totalXP,
+		WeeklyXP:            weeklyXP,
+		Level:               level,
+		LevelXP:             xpForLevel(level),
+		NextLevelXP:         xpForLevel(level + 1),
+		HideFromLeaderboard: user.HideFromLeaderboard,
+	}, nil
+}
+
+// GetLeaderboard menyusun leaderboard mingguan atau all-time. Scope mentor
+// berisi siswa satu pembimbing (untuk pembimbing: siswa bimbingannya), scope
+// room berisi pemilik dan user yang sedang online di room tersebut.
+func (s *XPService) GetLeaderboard(userIDString string, period string, scope string, roomIDQuery string, limitQuery string) (*dto.LeaderboardResponse, error) {
+	userID, err := strconv.ParseUint(userIDString, 10, 32)
+	if err != nil {
+		return nil, errors.New("user ID tidak valid")
+	}
+
+	if period == "" {
+		period = LeaderboardWeekly
+	}
+	if period != LeaderboardWeekly && period != LeaderboardAllTime {
+		return nil, errors.New("period harus weekly atau all_time")
+	}
+	if scope == "" {
+		scope = LeaderboardScopeGlobal
+	}
+
+	limit := defaultLeaderboardLimit
+	if limitQuery != "" {
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil || limit < 1 {
+			return nil, errors.New("parameter limit tidak valid")
+		}
+		limit = min(limit, maxLeaderboardLimit)
+	}
+
+	user, err := s.userRepo.FindByID(uint(userID))
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	var filter repository.LeaderboardFilter
+	switch scope {
+	case LeaderboardScopeGlobal:
+	case LeaderboardScopeMentor:
+		if user.Role == "pembimbing" {
+			filter.PembimbingID = &user.ID
+		} else if user.PembimbingID != nil {
+			filter.PembimbingID = user.PembimbingID
+		} else {
+			return nil, errors.New("anda belum terhubung dengan pembimbing")
+		}
+	case LeaderboardScopeRoom:
+		if roomIDQuery == "" {
+			return nil, errors.New("room_id wajib diisi untuk scope room")
+		}
+		filter.UserIDs, err = s.rooms.MemberIDs(roomIDQuery)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("scope harus global, mentor, atau room")
+	}
+
+	response := &dto.LeaderboardResponse{Period: period, Scope: scope, Entries: []dto.LeaderboardEntry{}}
+	if period == LeaderboardWeekly {
+		weekStart := startOfWeek(time.Now(), userLocation(user))
+		filter.Since = &weekStart
+		response.Since = &weekStart
+	}
+
+	rows, err := s.xpRepo.Leaderboard(filter, limit)
+	if err != nil {
+		return nil, errors.New("gagal mengambil leaderboard")
+	}
+	response.Entries = rankLeaderboard(rows)
+
+	if user.Role == "siswa" && !user.HideFromLeaderboard && inLeaderboardScope(user, filter) {
+		response.Me, err = s.leaderboardEntryFor(user, filter, response.Entries)
+		if err != nil {
+			return nil, errors.New("gagal mengambil leaderboard")
+		}
+	}
+
+	return response, nil
+}
+
+// leaderboardEntryFor mencari posisi user di leaderboard, termasuk saat user
+// berada di luar halaman teratas.
+func (s *XPService) leaderboardEntryFor(user *model.User, filter repository.LeaderboardFilter, entries []dto.LeaderboardEntry) (*dto.LeaderboardEntry, error) {
+	for _, entry := range entries {
+		if entry.UserID == user.ID {
+			return &entry, nil
+		}
+	}
+
+	xp, err := s.xpRepo.SumByUserID(user.ID, filter.Since)
+	if err != nil {
+		return nil, err
+	}
+	ahead, err := s.xpRepo.CountAhead(filter, xp)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LeaderboardEntry{Rank: int(ahead) + 1, UserID: user.ID, Username: user.Username, XP: xp}, nil
+}
+
+func inLeaderboardScope(user *model.User, filter repository.LeaderboardFilter) bool {
+	if filter.PembimbingID != nil && (user.PembimbingID == nil || *user.PembimbingID != *filter.PembimbingID) {
+		return false
+	}
+	if filter.UserIDs != nil {
+		return slices.Contains(filter.UserIDs, user.ID)
+	}
+	return true
+}
+
+// rankLeaderboard memberi peringkat dengan aturan "1224": user dengan XP sama
+// mendapat peringkat yang sama.
+func rankLeaderboard(rows []repository.LeaderboardRow) []dto.LeaderboardEntry {
+	entries := make([]dto.LeaderboardEntry, 0, len(rows))
+	for i, row := range rows {
+		rank := i + 1
+		if i > 0 && row.XP == rows[i-1].XP {
+			rank = entries[i-1].Rank
+		}
+		entries = append(entries, dto.LeaderboardEntry{Rank: rank, UserID: row.UserID, Username: row.Username, XP: row.XP})
+	}
+	return entries
+}
+
+// xpForLevel adalah total XP minimal untuk mencapai level tertentu. Level 1
+// dimulai dari 0 XP dan tiap level berikutnya butuh 100 XP lebih banyak dari
+// sebelumnya (0, 100, 300, 600, 1000, ...).
+func xpForLevel(level int) int {
+	return xpLevelStep * level * (level - 1) / 2
+}
+
+func levelForXP(xp int) int {
+	level := 1
+	for xpForLevel(level+1) <= xp {
+		level++
+	}
+	return level
+}
+
+// startOfWeek mengembalikan Senin pukul 00:00 minggu berjalan di zona loc.
+func startOfWeek(now time.Time, loc *time.Location) time.Time {
+	local := now.In(loc)
+	daysSinceMonday := (int(local.Weekday()) + 6) % 7
+	return time.Date(local.Year(), local.Month(), local.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+}
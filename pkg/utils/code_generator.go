@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+)
+
+const codeCharset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func GenerateRandomCode(length int) (string, error) {
+	code := make([]byte, length)
+	max := big.NewInt(int64(len(codeCharset)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = codeCharset[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func GenerateRandomToken(byteLength int) (string, error) {
+	buf := make([]byte, byteLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -8,38 +8,95 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var JWT_SECRET = []byte("ini_rahasia_banget_ganti_nanti")
+var (
+	jwtSecret []byte
+	jwtTTL    = 15 * time.Minute
+)
+
+const (
+	// streamTokenAudience menandai token yang hanya boleh dipakai untuk
+	// membuka koneksi SSE lewat query string.
+	streamTokenAudience = "stream"
+	streamTokenTTL      = time.Minute
+)
+
+func InitJWT(secret string, ttl time.Duration) error {
+	if secret == "" {
+		return errors.New("JWT_SECRET kosong")
+	}
+	jwtSecret = []byte(secret)
+	if ttl > 0 {
+		jwtTTL = ttl
+	}
+	return nil
+}
 
 type JWTClaims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID       string `json:"user_id"`
+	Role         string `json:"role"`
+	TokenVersion int    `json:"token_version"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, role string) (string, error) {
+func GenerateToken(userID uint, role string, tokenVersion int) (string, error) {
+	return signToken(userID, role, tokenVersion, jwtTTL, nil)
+}
+
+// GenerateStreamToken membuat token berumur pendek untuk EventSource, yang
+// tidak bisa mengirim header Authorization. Token ini ditolak ValidateToken,
+// jadi bocornya URL stream tidak memberi akses ke endpoint lain.
+func GenerateStreamToken(userID uint, role string, tokenVersion int) (string, error) {
+	return signToken(userID, role, tokenVersion, streamTokenTTL, jwt.ClaimStrings{streamTokenAudience})
+}
+
+func signToken(userID uint, role string, tokenVersion int, ttl time.Duration, audience jwt.ClaimStrings) (string, error) {
 	userIDStr := fmt.Sprint(userID)
 
 	claims := JWTClaims{
-		UserID: userIDStr,
-		Role:   role,
+		UserID:       userIDStr,
+		Role:         role,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			Audience:  audience,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(JWT_SECRET)
+	if len(jwtSecret) == 0 {
+		return "", errors.New("JWT belum diinisialisasi")
+	}
+	return token.SignedString(jwtSecret)
 }
 
 func ValidateToken(tokenString string) (*JWTClaims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if len(claims.Audience) > 0 {
+		return nil, errors.New("token stream tidak bisa dipakai sebagai access token")
+	}
+	return claims, nil
+}
+
+// ValidateStreamToken hanya menerima token dari GenerateStreamToken.
+func ValidateStreamToken(tokenString string) (*JWTClaims, error) {
+	return parseToken(tokenString, jwt.WithAudience(streamTokenAudience))
+}
+
+func parseToken(tokenString string, opts ...jwt.ParserOption) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return JWT_SECRET, nil
-	})
+		if len(jwtSecret) == 0 {
+			return nil, errors.New("JWT belum diinisialisasi")
+		}
+		return jwtSecret, nil
+	}, opts...)
 
 	if err != nil {
 		return nil, err
@@ -51,3 +108,11 @@ func ValidateToken(tokenString string) (*JWTClaims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+func AccessTokenTTL() time.Duration {
+	return jwtTTL
+}
+
+func StreamTokenTTL() time.Duration {
+	return streamTokenTTL
+}
@@ -2,41 +2,41 @@ package utils
 
 import (
 	"bytes"
-	"io"
-	"mime/multipart"
-	"strings"
+	"context"
 
 	"github.com/dslipak/pdf"
 )
 
-func ExtractTextFromPDF(file multipart.File, fileSize int64) (string, error) {
-	buf := new(bytes.Buffer)
-	if _, err := io.Copy(buf, file); err != nil {
-		return "", err
-	}
-
-	readerAt := bytes.NewReader(buf.Bytes())
-	r, err := pdf.NewReader(readerAt, fileSize)
+// ExtractPDFPages mengembalikan teks per halaman. Indeks 0 adalah halaman 1;
+// halaman kosong tetap ada sebagai string kosong supaya nomor halaman tidak
+// bergeser. Ekstraksi berhenti di antara halaman jika ctx selesai.
+func ExtractPDFPages(ctx context.Context, data []byte) ([]string, error) {
+	readerAt := bytes.NewReader(data)
+	r, err := pdf.NewReader(readerAt, int64(len(data)))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var allText strings.Builder
 	numPages := r.NumPage()
+	pages := make([]string, 0, numPages)
 
 	for i := 1; i <= numPages; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		page := r.Page(i)
 		if page.V.IsNull() {
+			pages = append(pages, "")
 			continue
 		}
 
 		text, err := page.GetPlainText(nil)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		allText.WriteString(text)
-		allText.WriteString("\n")
+		pages = append(pages, text)
 	}
 
-	return allText.String(), nil
+	return pages, nil
 }
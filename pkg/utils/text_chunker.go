@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"strings"
+)
+
+// EstimateTokens memperkirakan jumlah token dengan asumsi kasar
+// satu token kira-kira empat karakter.
+func EstimateTokens(text string) int {
+	return (len([]rune(text)) + 3) / 4
+}
+
+// ChunkText memecah teks menjadi potongan yang masing-masing kira-kira tidak
+// melebihi maxTokens. Pemecahan dilakukan di batas baris terlebih dahulu, lalu
+// di batas kata jika satu baris terlalu panjang.
+func ChunkText(text string, maxTokens int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if maxTokens <= 0 || EstimateTokens(text) <= maxTokens {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentRunes := 0
+
+	flush := func() {
+		if chunk := strings.TrimSpace(current.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+		currentRunes = 0
+	}
+
+	appendPiece := func(piece string, separator string) {
+		pieceRunes := len([]rune(piece))
+		if currentRunes > 0 && (currentRunes+len(separator)+pieceRunes+3)/4 > maxTokens {
+			flush()
+		}
+		if currentRunes > 0 {
+			current.WriteString(separator)
+			currentRunes += len(separator)
+		}
+		current.WriteString(piece)
+		currentRunes += pieceRunes
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if EstimateTokens(line) <= maxTokens {
+			appendPiece(line, "\n")
+			continue
+		}
+
+		for _, word := range strings.Fields(line) {
+			appendPiece(word, " ")
+		}
+	}
+	flush()
+
+	return chunks
+}
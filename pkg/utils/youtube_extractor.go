@@ -7,6 +7,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"google.golang.org/api/option"
@@ -29,25 +31,40 @@ func InitYouTubeService(apiKey string) error {
 	return nil
 }
 
-func ExtractTextFromYouTube(youtubeURL string) (string, error) {
+// YouTubeEnabled bernilai true jika InitYouTubeService sudah dipanggil dengan
+// API key.
+func YouTubeEnabled() bool {
+	return youtubeService != nil
+}
+
+// TranscriptSegment adalah satu baris transkrip beserta detik mulainya.
+type TranscriptSegment struct {
+	StartSeconds float64
+	Text         string
+}
+
+var transcriptStartPattern = regexp.MustCompile(`(?:start="([\d.]+)"|t="(\d+)")`)
+
+// ExtractYouTubeSegments mengambil transkrip video per baris, dipakai untuk
+// sitasi timestamp. Request dibatalkan jika ctx selesai.
+func ExtractYouTubeSegments(ctx context.Context, youtubeURL string) ([]TranscriptSegment, error) {
 	if youtubeService == nil {
-		return "", errors.New("YouTube service belum diinisialisasi")
+		return nil, errors.New("YouTube service belum diinisialisasi")
 	}
 
 	videoID, err := getVideoID(youtubeURL)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	ctx := context.Background()
 	call := youtubeService.Captions.List([]string{"snippet"}, videoID).Context(ctx)
 	response, err := call.Do()
 	if err != nil {
-		return "", fmt.Errorf("gagal mengambil daftar caption: %w", err)
+		return nil, fmt.Errorf("gagal mengambil daftar caption: %w", err)
 	}
 
 	if len(response.Items) == 0 {
-		return "", errors.New("video ini tidak memiliki caption/transkrip")
+		return nil, errors.New("video ini tidak memiliki caption/transkrip")
 	}
 
 	var captionTrack *youtube.Caption
@@ -63,23 +80,27 @@ func ExtractTextFromYouTube(youtubeURL string) (string, error) {
 	}
 
 	if captionTrack == nil {
-		return "", errors.New("tidak ditemukan transkrip 'id' atau 'en'")
+		return nil, errors.New("tidak ditemukan transkrip 'id' atau 'en'")
 	}
 
 	downloadURL := fmt.Sprintf("https://www.youtube.com/api/timedtext?v=%s&lang=%s&fmt=srv3", videoID, captionTrack.Snippet.Language)
-	resp, err := http.Get(downloadURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("gagal download transkrip: %w", err)
+		return nil, fmt.Errorf("gagal membuat request transkrip: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gagal download transkrip: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("gagal baca body transkrip: %w", err)
+		return nil, fmt.Errorf("gagal baca body transkrip: %w", err)
 	}
 
 	xmlData := string(body)
-	var allText strings.Builder
+	var segments []TranscriptSegment
 	lines := strings.Split(xmlData, "</text>")
 	for _, line := range lines {
 		start := strings.Index(line, `">`)
@@ -87,12 +108,21 @@ func ExtractTextFromYouTube(youtubeURL string) (string, error) {
 			text := line[start+2:]
 			text = strings.ReplaceAll(text, "&#39;", "'")
 			text = strings.ReplaceAll(text, "&amp;", "&")
-			allText.WriteString(text)
-			allText.WriteString(" ")
+
+			segment := TranscriptSegment{Text: text}
+			if match := transcriptStartPattern.FindStringSubmatch(line[:start+1]); match != nil {
+				if match[1] != "" {
+					segment.StartSeconds, _ = strconv.ParseFloat(match[1], 64)
+				} else {
+					millis, _ := strconv.ParseFloat(match[2], 64)
+					segment.StartSeconds = millis / 1000
+				}
+			}
+			segments = append(segments, segment)
 		}
 	}
 
-	return allText.String(), nil
+	return segments, nil
 }
 
 func getVideoID(youtubeURL string) (string, error) {